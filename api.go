@@ -5,10 +5,15 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/Drelf2018/req"
@@ -22,13 +27,21 @@ type At struct {
 	AtUserIDs []string `json:"atUserIds,omitempty"` // 被@的群成员 userId
 }
 
+// IsZero 检测是否没有@任何人
+func (a At) IsZero() bool {
+	return !a.IsAtAll && len(a.AtMobiles) == 0 && len(a.AtUserIDs) == 0
+}
+
 // Send 自定义机器人发送群消息
 type Send struct {
 	// 要发送的消息
 	Msg Msg
 
+	// 自定义请求地址，不为空时替代默认的发送消息接口，例如接收消息中的 sessionWebhook
+	Webhook string
+
 	// 自定义机器人调用接口的凭证
-	AccessToken string `req:"query"`
+	AccessToken string `req:"query,omitempty"`
 
 	// 使用时间戳和密钥生成的加密签名
 	Sign string `req:"query,omitempty"`
@@ -42,31 +55,178 @@ type Send struct {
 	// 被@的群成员信息
 	At At `req:"body,omitempty"`
 
+	// 请求标识，不为空时作为请求头 X-Request-ID 发送
+	RequestID string `req:"header:X-Request-ID,omitempty"`
+
 	// 请求头
 	ContentType string `req:"header" default:"application/json"`
+
+	// 额外的查询参数，通过 AddQuery 添加，会覆盖同名的参数
+	ExtraQuery map[string]string
+
+	// 额外的请求体字段，通过 AddBody 添加，会覆盖同名的字段
+	ExtraBody map[string]any
+
+	// 发送使用的上下文，通过 Context 获取
+	ctx context.Context
+
+	// 重试策略，通过 Retry 或 RetryPolicy.Handler 设置
+	retry *RetryPolicy
+
+	// 需要同时发送副本的其他机器人凭证，通过 Duplicate 设置
+	duplicates []string
+
+	// 发送前需要等待的限流器，通过 RateLimit 或 RateLimiter.Handler 设置
+	limiters []*RateLimiter
+
+	// 发送请求前调用的钩子函数，通过 OnBeforeSend 注册
+	beforeSend []BeforeSendHook
+
+	// 请求结束后调用的钩子函数，通过 OnAfterSend 注册
+	afterSend []AfterSendHook
+}
+
+// Context 返回发送使用的上下文，处理器可以据此等待或取消，未通过 SendWithContext 等方法创建的请求返回 context.Background()
+func (s *Send) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
 }
 
 func (*Send) Method() string {
 	return http.MethodPost
 }
 
-func (*Send) RawURL() string {
-	return "https://oapi.dingtalk.com/robot/send"
+// DefaultWebhook 自定义机器人发送群消息的接口地址
+const DefaultWebhook = "https://oapi.dingtalk.com/robot/send"
+
+func (s *Send) RawURL() string {
+	if s.Webhook != "" {
+		return s.Webhook
+	}
+	return DefaultWebhook
 }
 
 var _ req.API = (*Send)(nil)
 
-func (s *Send) Body(r *http.Request, value reflect.Value, body []reflect.StructField) (io.Reader, error) {
-	m := method.MakeJSONMap(r.Context(), value, body)
-	if s.Msg != nil {
-		m["msgtype"] = s.Msg.Type()
-		m[string(s.Msg.Type())] = s.Msg
+// Query 将请求参数合并进请求地址中已有的参数，避免覆盖 Webhook 自带的参数
+func (s *Send) Query(r *http.Request, value reflect.Value, query []reflect.StructField) error {
+	q := r.URL.Query()
+	s.mergeQuery(r.Context(), q, value, query)
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// mergeQuery 将请求参数和额外的查询参数合并进 q
+func (s *Send) mergeQuery(ctx context.Context, q url.Values, value reflect.Value, query []reflect.StructField) {
+	for k, v := range method.MakeURLValues(ctx, value, query) {
+		q[k] = v
+	}
+	for k, v := range s.ExtraQuery {
+		q.Set(k, v)
+	}
+}
+
+// AddQuery 添加额外的查询参数，用于钉钉尚未写入文档的参数
+func (s *Send) AddQuery(key, value string) *Send {
+	if s.ExtraQuery == nil {
+		s.ExtraQuery = make(map[string]string)
+	}
+	s.ExtraQuery[key] = value
+	return s
+}
+
+// AddBody 添加额外的请求体顶层字段，用于钉钉尚未写入文档的字段
+func (s *Send) AddBody(key string, value any) *Send {
+	if s.ExtraBody == nil {
+		s.ExtraBody = make(map[string]any)
+	}
+	s.ExtraBody[key] = value
+	return s
+}
+
+var _ req.APIQuery = (*Send)(nil)
+
+// Endpoint 返回发送请求时的完整地址，包括所有查询参数，用于日志和调试，
+// 凭证通过 maskToken 隐藏，地址无法解析时返回空字符串
+func (s *Send) Endpoint() string {
+	u, err := url.Parse(s.RawURL())
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	s.mergeQuery(context.Background(), q, reflect.ValueOf(s).Elem(), method.LoadTask(s).Query)
+	token := q.Get("access_token")
+	q.Del("access_token")
+	u.RawQuery = q.Encode()
+	if token != "" {
+		prefix := strings.TrimSuffix(maskToken(token), tokenMask)
+		masked := "access_token=" + url.QueryEscape(prefix) + tokenMask
+		if u.RawQuery == "" {
+			u.RawQuery = masked
+		} else {
+			u.RawQuery = masked + "&" + u.RawQuery
+		}
 	}
-	return method.NewJSONReader(m)
+	return u.String()
+}
+
+// payload 生成请求体对应的 map[string]any
+func (s *Send) payload(ctx context.Context, value reflect.Value, body []reflect.StructField) map[string]any {
+	m := method.MakeJSONMap(ctx, value, body)
+	switch msg := s.Msg.(type) {
+	case nil:
+	case RawMsg:
+		maps.Copy(m, msg.Body)
+	default:
+		m["msgtype"] = msg.Type()
+		m[string(msg.Type())] = msg
+	}
+	maps.Copy(m, s.ExtraBody)
+	return m
+}
+
+// body 不依赖 *http.Request 生成与 Body 相同的请求体
+func (s *Send) body(ctx context.Context) ([]byte, error) {
+	return json.Marshal(s.payload(ctx, reflect.ValueOf(s).Elem(), method.LoadTask(s).Body))
+}
+
+func (s *Send) Body(r *http.Request, value reflect.Value, body []reflect.StructField) (io.Reader, error) {
+	return method.NewJSONReader(s.payload(r.Context(), value, body))
 }
 
 var _ req.APIBody = (*Send)(nil)
 
+// CheckResponse 响应状态码不为 200 时返回错误码为状态码的 SendError ，使重试等逻辑可以按错误码判断
+func (s *Send) CheckResponse(_ *http.Client, resp *http.Response, _ req.API) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = resp.Status
+	}
+	return SendError{
+		API:        s,
+		ErrMsg:     msg,
+		ErrCode:    resp.StatusCode,
+		TraceID:    resp.Header.Get(TraceIDHeader),
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+var _ req.CheckResponse = (*Send)(nil)
+
+// MarshalJSON 生成与 Body 完全相同的请求体，包括 msgtype 、消息内容、 at 和 msgUuid
+func (s *Send) MarshalJSON() ([]byte, error) {
+	return s.body(context.Background())
+}
+
+var _ json.Marshaler = (*Send)(nil)
+
 // GenerateSign 生成加密时间戳和签名，加签的方式是将时间戳和密钥当做签名字符串，
 // 开发者服务内当前系统时间戳，单位是毫秒，与请求调用时间误差不能超过 1 小时，
 // 使用 HmacSHA256 算法计算签名，然后进行 Base64 编码，得到最终的签名
@@ -111,64 +271,164 @@ func UUID(uuid string) SendHandler {
 	}
 }
 
+// Webhook 设置自定义请求地址，并清空调用接口的凭证
+func Webhook(url string) SendHandler {
+	return func(s *Send) error {
+		s.Webhook = url
+		s.AccessToken = ""
+		return nil
+	}
+}
+
 // AtAll @所有人
 func AtAll(s *Send) error {
 	s.At.IsAtAll = true
 	return nil
 }
 
-// AtMobile @指定群成员手机号
+// AtMobile @指定群成员手机号，追加至已有的手机号并去除重复，例如机器人的 DefaultAt
 func AtMobile(mobiles ...string) SendHandler {
 	return func(s *Send) error {
-		s.At.AtMobiles = mobiles
+		s.At.AtMobiles = appendUnique(s.At.AtMobiles, mobiles...)
 		return nil
 	}
 }
 
-// AtUserID @指定群成员 userId
+// AtUserID @指定群成员 userId ，追加至已有的 userId 并去除重复，例如机器人的 DefaultAt
 func AtUserID(ids ...string) SendHandler {
 	return func(s *Send) error {
-		s.At.AtUserIDs = ids
+		s.At.AtUserIDs = appendUnique(s.At.AtUserIDs, ids...)
 		return nil
 	}
 }
 
-// 内置了六个常用的处理器，可自行在代码中查看使用方法
-var _ = []SendHandler{UpdateMsg[Msg](nil), Secret(""), UUID(""), AtAll, AtMobile(""), AtUserID("")}
+// AtClear 清除所有@配置，包括机器人的 DefaultAt
+func AtClear(s *Send) error {
+	s.At = At{}
+	return nil
+}
+
+// 内置了十四个常用的处理器，可自行在代码中查看使用方法
+var _ = []SendHandler{UpdateMsg[Msg](nil), Secret(""), UUID(""), Webhook(""), AtAll, AtMobile(""), AtUserID(""), AtClear, SlogHandler(nil, slog.LevelInfo), Quiet(time.Time{}, time.Time{}), QuietHours(0, 0), Duplicate(nil), RateLimit(0, 0), Retry(0, 0, 0)}
+
+// TraceIDHeader 钉钉响应头中的链路标识，反馈问题时可以提供该值
+const TraceIDHeader = "X-DingTalk-Trace-Id"
 
 // SendResponse 发送消息响应体
 type SendResponse struct {
 	ErrMsg  string `json:"errmsg"`
 	ErrCode int    `json:"errcode"`
+
+	// 响应头 X-DingTalk-Trace-Id 的值
+	TraceID string `json:"-"`
 }
 
-// SendError 发送消息错误
+// SendError 发送消息错误，响应状态码不为 200 时错误码为 HTTP 状态码
 type SendError struct {
 	API     *Send
 	ErrMsg  string
 	ErrCode int
+	TraceID string
+
+	// 响应头 Retry-After 中建议的重试间隔
+	retryAfter time.Duration
 }
 
 func (s SendError) Error() string {
 	return fmt.Sprintf("dingtalk: failed to send %T: %s (%d)", s.API.Msg, s.ErrMsg, s.ErrCode)
 }
 
+// Is 目标为 *CodedError 时按错误码匹配，使 errors.Is(err, ErrRateLimit) 等判断可以直接使用
+func (s SendError) Is(target error) bool {
+	c, ok := target.(*CodedError)
+	return ok && c != nil && s.ErrCode == c.Code
+}
+
+// CodedError 钉钉接口的错误码，用于 errors.Is 匹配 SendError
+type CodedError struct {
+	Code int
+}
+
+func (c *CodedError) Error() string {
+	return fmt.Sprintf("dingtalk: error code %d", c.Code)
+}
+
+// ErrorCode 新建错误码
+func ErrorCode(code int) *CodedError {
+	return &CodedError{Code: code}
+}
+
+// 常见的钉钉接口错误码
+var (
+	ErrRateLimit        = ErrorCode(43)     // 发送过于频繁
+	ErrSendTooFast      = ErrorCode(130101) // 超过每分钟 20 条消息的发送限制
+	ErrInvalidToken     = ErrorCode(300001) // 凭证无效
+	ErrSignatureExpired = ErrorCode(310000) // 签名不匹配或时间戳过期
+)
+
+// SendResult 发送消息结果
+type SendResult struct {
+	// 响应体
+	Response SendResponse
+
+	// 经过处理器处理后实际发送的请求
+	Request *Send
+
+	// 响应头 X-DingTalk-Trace-Id 的值
+	TraceID string
+
+	// 请求耗时
+	Latency time.Duration
+}
+
 // PostSendWithContext 携带上下文发送消息
-func PostSendWithContext(ctx context.Context, token string, msg Msg, handlers ...SendHandler) (r SendResponse, err error) {
-	api := &Send{Msg: msg, AccessToken: token}
+func PostSendWithContext(ctx context.Context, token string, msg Msg, handlers ...SendHandler) (r SendResult, err error) {
+	api := &Send{Msg: msg, AccessToken: token, ctx: ctx}
+	r.Request = api
 	for _, handler := range handlers {
 		if err = handler(api); err != nil {
 			return
 		}
 	}
-	r, err = req.ResultWithContext[SendResponse](ctx, api)
-	if err == nil && r.ErrCode != 0 {
-		err = SendError{API: api, ErrMsg: r.ErrMsg, ErrCode: r.ErrCode}
+	if err = api.waitLimiters(ctx); err != nil {
+		return
+	}
+	api.runBeforeSend(ctx, nil)
+	start := time.Now()
+	r.Response, err = postSendWithDuplicates(ctx, req.DefaultSession, api)
+	r.Latency = time.Since(start)
+	r.TraceID = r.Response.TraceID
+	api.runAfterSend(ctx, nil, r.Response, err)
+	return
+}
+
+// postSend 发送已经处理完毕的请求，响应中错误码不为零时返回 SendError
+func postSend(ctx context.Context, session *req.Session, api *Send) (r SendResponse, err error) {
+	resp, err := session.DoWithContext(ctx, api)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	if err != nil {
+		return r, fmt.Errorf("dingtalk: failed to decode response: %w", err)
+	}
+	r.TraceID = resp.Header.Get(TraceIDHeader)
+	if r.ErrCode != 0 {
+		err = SendError{API: api, ErrMsg: r.ErrMsg, ErrCode: r.ErrCode, TraceID: r.TraceID, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
 	}
 	return
 }
 
-// PostSendWithContext 发送消息
-func PostSend(token string, msg Msg, handlers ...SendHandler) (SendResponse, error) {
+// PostSend 发送消息
+func PostSend(token string, msg Msg, handlers ...SendHandler) (SendResult, error) {
 	return PostSendWithContext(context.Background(), token, msg, handlers...)
 }
+
+// PostSendLegacy 发送消息并仅返回响应体，保留了 PostSend 旧的函数签名
+//
+// Deprecated: 请使用 PostSend 获取完整的发送结果，该函数将在下个版本移除
+func PostSendLegacy(token string, msg Msg, handlers ...SendHandler) (SendResponse, error) {
+	r, err := PostSend(token, msg, handlers...)
+	return r.Response, err
+}
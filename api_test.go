@@ -0,0 +1,274 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// replyTrace 返回携带链路标识的响应
+func replyTrace(traceID string, code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(TraceIDHeader, traceID)
+		replyError(code, "msg")(w, r)
+	}
+}
+
+func TestPostSendTraceID(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyTrace("trace-ok", 0))
+	r, err := PostSend(testToken, Text{Content: "hello"}, Webhook(srv.URL), func(s *Send) error {
+		s.RequestID = "req-1"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.TraceID != "trace-ok" || r.Response.TraceID != "trace-ok" {
+		t.Fatalf("TraceID = %q/%q, want trace-ok", r.TraceID, r.Response.TraceID)
+	}
+	if r.Request == nil || r.Request.RequestID != "req-1" {
+		t.Fatalf("Request = %v, want the sent request", r.Request)
+	}
+	if got := srv.Last(t).Header.Get("X-Request-ID"); got != "req-1" {
+		t.Fatalf("X-Request-ID = %q, want req-1", got)
+	}
+}
+
+func TestSendErrorTraceID(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyTrace("trace-err", ErrInvalidToken.Code))
+	err := srv.Bot().SendWithContext(context.Background(), Text{Content: "hello"})
+	var se SendError
+	if !errors.As(err, &se) {
+		t.Fatalf("SendWithContext() error = %v, want SendError", err)
+	}
+	if se.TraceID != "trace-err" || se.ErrCode != ErrInvalidToken.Code {
+		t.Fatalf("SendError = %+v, want trace-err and code %d", se, ErrInvalidToken.Code)
+	}
+	if srv.Last(t).Header.Get("X-Request-ID") != "" {
+		t.Fatal("empty RequestID should not be sent")
+	}
+}
+
+func TestPostSendResult(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyTrace("trace-ok", 0))
+	msg := Text{Content: "hello"}
+	r, err := PostSendWithContext(context.Background(), testToken, msg, Webhook(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Response.ErrMsg != "msg" || r.Response.ErrCode != 0 || r.TraceID != "trace-ok" || r.Latency <= 0 || r.Request == nil || r.Request.Msg != msg {
+		t.Fatalf("PostSendWithContext() = %+v, want all fields populated", r)
+	}
+
+	srv.SetHandler(replyTrace("trace-err", ErrInvalidToken.Code))
+	r, err = PostSend(testToken, msg, Webhook(srv.URL))
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("PostSend() error = %v, want ErrInvalidToken", err)
+	}
+	if r.Response.ErrCode != ErrInvalidToken.Code || r.TraceID != "trace-err" || r.Latency <= 0 || r.Request == nil {
+		t.Fatalf("PostSend() = %+v, want all fields populated on error", r)
+	}
+
+	resp, err := PostSendLegacy(testToken, msg, Webhook(srv.URL))
+	if !errors.Is(err, ErrInvalidToken) || resp.ErrCode != ErrInvalidToken.Code || resp.TraceID != "trace-err" {
+		t.Fatalf("PostSendLegacy() = %+v, %v", resp, err)
+	}
+}
+
+func TestSendMarshalJSON(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Secret = "SECtest"
+	msgs := []Msg{
+		Text{Content: "hello"},
+		Markdown{Title: "标题", Text: "# 正文 <>&"},
+		ActionsCard{Title: "t", Text: "t", Btns: []ActionCardBtn{{Title: "a", ActionURL: "https://example.com?a=1&b=2"}}},
+		FeedCard{Links: feedLinks(2)},
+	}
+	for _, msg := range msgs {
+		t.Run(string(msg.Type()), func(t *testing.T) {
+			handlers := []SendHandler{UUID("uuid-" + string(msg.Type()))}
+			if msg.Type().AtSupported() {
+				handlers = append(handlers, AtMobile("13800000000"), AtUserID("user"))
+			}
+			api, err := bot.NewSend(context.Background(), msg, handlers...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := api.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			serialized, err := bot.Serialize(context.Background(), msg, handlers...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(serialized, want) {
+				t.Fatalf("Serialize() = %s, want %s", serialized, want)
+			}
+			if err := bot.Send(msg, handlers...); err != nil {
+				t.Fatal(err)
+			}
+			if got := srv.Last(t).Body; !bytes.Equal(got, want) {
+				t.Fatalf("server received %s, want MarshalJSON() %s", got, want)
+			}
+		})
+	}
+}
+
+func TestSendErrorIs(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	for _, target := range []*CodedError{ErrRateLimit, ErrInvalidToken, ErrSignatureExpired} {
+		srv.SetHandler(replyError(target.Code, "failed"))
+		err := bot.SendText("coded")
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(%v, %v) = false", err, target)
+		}
+		if errors.Is(err, ErrorCode(target.Code+1)) {
+			t.Errorf("errors.Is(%v, code %d) = true", err, target.Code+1)
+		}
+		if !errors.Is(fmt.Errorf("wrapped: %w", err), ErrorCode(target.Code)) {
+			t.Errorf("wrapped error does not match code %d", target.Code)
+		}
+	}
+	if errors.Is(SendError{ErrCode: 43}, errors.New("dingtalk: error code 43")) {
+		t.Fatal("SendError matched a non-coded error")
+	}
+	if got := ErrRateLimit.Error(); got != "dingtalk: error code 43" {
+		t.Fatalf("ErrRateLimit.Error() = %q", got)
+	}
+}
+
+func TestSendBodyHideAvatar(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	card := ActionCard{Title: "t", Text: "x", SingleTitle: "more", SingleURL: "https://example.com"}
+
+	if err := bot.Send(card.WithHideAvatar(true)); err != nil {
+		t.Fatal(err)
+	}
+	raw := srv.Last(t).Body
+	if !bytes.Contains(raw, []byte(`"actionCard":{`)) || !bytes.Contains(raw, []byte(`"hideAvatar":"1"`)) {
+		t.Fatalf("raw body %s missing actionCard.hideAvatar", raw)
+	}
+	body := srv.Last(t).JSON(t)
+	if _, ok := body["hideAvatar"]; ok {
+		t.Fatalf("hideAvatar serialized at the top level: %s", raw)
+	}
+	if got := body["actionCard"].(map[string]any)["hideAvatar"]; got != "1" {
+		t.Fatalf("actionCard.hideAvatar = %v, want \"1\"", got)
+	}
+
+	if err := bot.Send(card); err != nil {
+		t.Fatal(err)
+	}
+	if raw := srv.Last(t).Body; bytes.Contains(raw, []byte("hideAvatar")) {
+		t.Fatalf("zero HideAvatar serialized: %s", raw)
+	}
+
+	if err := bot.Send(ActionsCard{Title: "t", Text: "x", Btns: []ActionCardBtn{{Title: "a", ActionURL: "https://example.com"}}}.WithHideAvatar(true)); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["actionCard"].(map[string]any)["hideAvatar"]; got != "1" {
+		t.Fatalf("ActionsCard actionCard.hideAvatar = %v, want \"1\"", got)
+	}
+}
+
+func TestSendEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		api  *Send
+		want string
+	}{
+		{"default", &Send{AccessToken: testToken}, DefaultWebhook + "?access_token=01234567***"},
+		{"signed", &Send{AccessToken: testToken, Sign: "a+b", Timestamp: 1700000000000}, DefaultWebhook + "?access_token=01234567***&sign=a%2Bb&timestamp=1700000000000"},
+		{"webhook", &Send{Webhook: "https://example.com/robot?session=x", AccessToken: testToken}, "https://example.com/robot?access_token=01234567***&session=x"},
+		{"extra", (&Send{}).AddQuery("debug", "1"), DefaultWebhook + "?debug=1"},
+		{"short", &Send{AccessToken: "short"}, DefaultWebhook + "?access_token=***"},
+		{"invalid", &Send{Webhook: "://"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.api.Endpoint(); got != tt.want {
+				t.Fatalf("Endpoint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if got := (&Send{AccessToken: testToken}).Endpoint(); bytes.Contains([]byte(got), []byte(testToken)) {
+		t.Fatalf("Endpoint() = %s leaks the token", got)
+	}
+}
+
+func TestBotNewSend(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Secret = "SECtest"
+	bot.Keywords = []string{"告警"}
+	bot.DefaultAt = At{AtMobiles: []string{"13800000000"}}
+	bot.DefaultHandlers = []SendHandler{UUID("uuid-default")}
+
+	api, err := bot.NewSend(context.Background(), Text{Content: "磁盘已满"}, AtUserID("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("NewSend() sent %d requests", n)
+	}
+	if got := api.Msg.(Text).Content; got != "磁盘已满告警" {
+		t.Fatalf("Content = %q, want the keyword injected", got)
+	}
+	if api.AccessToken != testToken || api.Webhook != srv.URL || api.MsgUUID != "uuid-default" {
+		t.Fatalf("NewSend() = %+v, want bot token, base url and default handlers applied", api)
+	}
+	if api.Sign == "" || api.Timestamp == 0 {
+		t.Fatal("NewSend() did not sign the request")
+	}
+	if want := []string{"user"}; fmt.Sprint(api.At.AtUserIDs) != fmt.Sprint(want) {
+		t.Fatalf("AtUserIDs = %v, want %v", api.At.AtUserIDs, want)
+	}
+
+	want, err := api.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.SendText("磁盘已满", AtUserID("user")); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).Body; !bytes.Equal(got, want) {
+		t.Fatalf("server received %s, want NewSend() body %s", got, want)
+	}
+
+	if _, err := (*Bot)(nil).NewSend(context.Background(), Text{}); !errors.Is(err, ErrNilBot) {
+		t.Fatalf("NewSend() on nil bot = %v, want ErrNilBot", err)
+	}
+}
+
+func TestSendExtraFields(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	err := bot.SendText("extra", func(s *Send) error {
+		s.AddQuery("debug", "1").AddQuery("debug", "2").AddBody("beta", map[string]any{"enabled": true}).AddBody("msgUuid", "uuid-extra")
+		return nil
+	}, UUID("uuid-handler"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := srv.Last(t)
+	if got := r.Query.Get("debug"); got != "2" || r.Query.Get("access_token") != testToken {
+		t.Fatalf("query = %v, want debug=2 alongside access_token", r.Query)
+	}
+	body := r.JSON(t)
+	if beta, ok := body["beta"].(map[string]any); !ok || beta["enabled"] != true {
+		t.Fatalf("beta = %v, want the extra body field", body["beta"])
+	}
+	if body["msgUuid"] != "uuid-extra" || body["msgtype"] != "text" {
+		t.Fatalf("body = %v, want extra fields to override msgUuid only", body)
+	}
+}
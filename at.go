@@ -0,0 +1,96 @@
+package dingtalk
+
+import (
+	"slices"
+	"sort"
+)
+
+// Clone 返回@配置的拷贝，其中的切片不会与原配置共享底层数组
+func (a At) Clone() At {
+	a.AtMobiles = slices.Clone(a.AtMobiles)
+	a.AtUserIDs = slices.Clone(a.AtUserIDs)
+	return a
+}
+
+// Equals 检测两个@配置是否相同，比较手机号和 userId 时不考虑顺序
+func (a At) Equals(other At) bool {
+	return a.IsAtAll == other.IsAtAll && sortedEqual(a.AtMobiles, other.AtMobiles) && sortedEqual(a.AtUserIDs, other.AtUserIDs)
+}
+
+// sortedEqual 检测两个字符串切片排序后是否相同，不会修改传入的切片
+func sortedEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return slices.Equal(a, b)
+}
+
+// Contains 检测手机号 mobile 或 userId 为 userID 的群成员是否被@，参数为空时不参与检测
+func (a At) Contains(mobile, userID string) bool {
+	return mobile != "" && slices.Contains(a.AtMobiles, mobile) || userID != "" && slices.Contains(a.AtUserIDs, userID)
+}
+
+// appendUnique 将 values 中不在 dst 里的值追加至 dst 的拷贝，不会修改传入的切片
+func appendUnique(dst []string, values ...string) []string {
+	dst = slices.Clip(dst)
+	for _, v := range values {
+		if !slices.Contains(dst, v) {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+// defaultAt 返回默认@配置的拷贝
+func (b *Bot) defaultAt() At {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.DefaultAt.Clone()
+}
+
+// AtAll 默认@所有人
+func (b *Bot) AtAll() *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	b.DefaultAt.IsAtAll = true
+	b.mu.Unlock()
+	return b
+}
+
+// AtMobiles 追加默认@的群成员手机号
+func (b *Bot) AtMobiles(mobiles ...string) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	b.DefaultAt.AtMobiles = append(append(make([]string, 0, len(b.DefaultAt.AtMobiles)+len(mobiles)), b.DefaultAt.AtMobiles...), mobiles...)
+	b.mu.Unlock()
+	return b
+}
+
+// AtUserIDs 追加默认@的群成员 userId
+func (b *Bot) AtUserIDs(ids ...string) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	b.DefaultAt.AtUserIDs = append(append(make([]string, 0, len(b.DefaultAt.AtUserIDs)+len(ids)), b.DefaultAt.AtUserIDs...), ids...)
+	b.mu.Unlock()
+	return b
+}
+
+// ClearDefaultAt 清除默认@配置
+func (b *Bot) ClearDefaultAt() *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	b.DefaultAt = At{}
+	b.mu.Unlock()
+	return b
+}
@@ -0,0 +1,168 @@
+package dingtalk
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// sentAt 返回请求体中的@配置
+func sentAt(t *testing.T, r testRequest) map[string]any {
+	t.Helper()
+	at, _ := r.JSON(t)["at"].(map[string]any)
+	return at
+}
+
+func TestDefaultAt(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().AtMobiles("13800000000").AtMobiles("13900000000").AtUserIDs("user")
+
+	if err := bot.SendText("default", AtUserID("extra")); err != nil {
+		t.Fatalf("SendText() = %v", err)
+	}
+	at := sentAt(t, srv.Last(t))
+	if want := []any{"13800000000", "13900000000"}; !reflect.DeepEqual(at["atMobiles"], want) {
+		t.Errorf("atMobiles = %v, want %v", at["atMobiles"], want)
+	}
+	if want := []any{"user", "extra"}; !reflect.DeepEqual(at["atUserIds"], want) {
+		t.Errorf("atUserIds = %v, want per-call handler merged into DefaultAt %v", at["atUserIds"], want)
+	}
+	if got := bot.DefaultAt.AtUserIDs; !reflect.DeepEqual(got, []string{"user"}) {
+		t.Errorf("per-call handler modified DefaultAt: %q", got)
+	}
+
+	if err := bot.SendText("cleared", AtClear); err != nil {
+		t.Fatalf("SendText(AtClear) = %v", err)
+	}
+	if at := sentAt(t, srv.Last(t)); at["atMobiles"] != nil || at["atUserIds"] != nil || at["isAtAll"] == true {
+		t.Errorf("AtClear sent at = %v", at)
+	}
+
+	if err := bot.AtAll().SendText("all"); err != nil {
+		t.Fatalf("SendText() = %v", err)
+	}
+	if at := sentAt(t, srv.Last(t)); at["isAtAll"] != true {
+		t.Errorf("isAtAll = %v, want true", at["isAtAll"])
+	}
+
+	if err := bot.Send(Link{Title: "t", Text: "t", MessageURL: "https://example.com"}); err != nil {
+		t.Fatalf("Send(Link) with DefaultAt = %v, want DefaultAt skipped", err)
+	}
+
+	if !bot.ClearDefaultAt().DefaultAt.IsZero() {
+		t.Fatalf("ClearDefaultAt() left %+v", bot.DefaultAt)
+	}
+}
+
+func TestDefaultAtMerge(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().AtMobiles("13800000000").AtUserIDs("user")
+	err := bot.SendText("merge", AtMobile("13900000000", "13800000000"), AtUserID("user", "extra"), AtMobile("13900000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := sentAt(t, srv.Last(t))
+	if want := []any{"13800000000", "13900000000"}; !reflect.DeepEqual(at["atMobiles"], want) {
+		t.Errorf("atMobiles = %v, want %v", at["atMobiles"], want)
+	}
+	if want := []any{"user", "extra"}; !reflect.DeepEqual(at["atUserIds"], want) {
+		t.Errorf("atUserIds = %v, want %v", at["atUserIds"], want)
+	}
+	if !reflect.DeepEqual(bot.DefaultAt, At{AtMobiles: []string{"13800000000"}, AtUserIDs: []string{"user"}}) {
+		t.Errorf("per-call handlers modified DefaultAt: %+v", bot.DefaultAt)
+	}
+
+	// 单独使用时与之前相同，且不会写入调用方的切片
+	mobiles := make([]string, 1, 2)
+	mobiles[0] = "13800000000"
+	api := &Send{At: At{AtMobiles: mobiles}}
+	if err := AtMobile("13900000000")(api); err != nil {
+		t.Fatal(err)
+	}
+	if mobiles[:2][1] != "" || len(api.At.AtMobiles) != 2 {
+		t.Fatalf("AtMobile() = %q, wrote into the existing slice: %q", api.At.AtMobiles, mobiles[:2])
+	}
+}
+
+func TestDefaultAtConcurrent(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			bot.AtMobiles("13800000000").AtUserIDs("user").AtAll()
+		}()
+		go func() {
+			defer wg.Done()
+			if err := bot.SendText("concurrent"); err != nil {
+				t.Errorf("SendText() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(bot.DefaultAt.AtMobiles); n != 8 {
+		t.Fatalf("got %d default mobiles, want 8", n)
+	}
+	bot.ClearDefaultAt()
+}
+
+func TestAtClone(t *testing.T) {
+	a := At{IsAtAll: true, AtMobiles: []string{"13800000000"}, AtUserIDs: []string{"user"}}
+	c := a.Clone()
+	c.AtMobiles[0] = "changed"
+	c.AtUserIDs = append(c.AtUserIDs[:0], "changed")
+	if a.AtMobiles[0] != "13800000000" || a.AtUserIDs[0] != "user" {
+		t.Fatalf("Clone() shares slices with the original: %+v", a)
+	}
+	if c := (At{}).Clone(); c.AtMobiles != nil || c.AtUserIDs != nil {
+		t.Fatalf("Clone() of zero At = %+v, want nil slices", c)
+	}
+}
+
+func TestAtEquals(t *testing.T) {
+	a := At{AtMobiles: []string{"1", "2"}, AtUserIDs: []string{"a", "b"}}
+	tests := []struct {
+		other At
+		want  bool
+	}{
+		{At{AtMobiles: []string{"2", "1"}, AtUserIDs: []string{"b", "a"}}, true},
+		{At{AtMobiles: []string{"1", "2"}, AtUserIDs: []string{"a", "b"}, IsAtAll: true}, false},
+		{At{AtMobiles: []string{"1", "1"}, AtUserIDs: []string{"a", "b"}}, false},
+		{At{AtMobiles: []string{"1", "2"}, AtUserIDs: []string{"a"}}, false},
+		{At{AtMobiles: []string{"1", "2"}}, false},
+	}
+	for _, tt := range tests {
+		if got := a.Equals(tt.other); got != tt.want {
+			t.Errorf("Equals(%+v) = %v, want %v", tt.other, got, tt.want)
+		}
+	}
+	b := At{AtMobiles: []string{"2", "1"}}
+	b.Equals(At{AtMobiles: []string{"1", "2"}})
+	if b.AtMobiles[0] != "2" {
+		t.Fatal("Equals() sorted the receiver's slice")
+	}
+	if !(At{}).Equals(At{AtMobiles: []string{}}) {
+		t.Fatal("Equals() distinguishes nil and empty slices")
+	}
+}
+
+func TestAtContains(t *testing.T) {
+	a := At{AtMobiles: []string{"13800000000"}, AtUserIDs: []string{"user"}}
+	tests := []struct {
+		mobile, userID string
+		want           bool
+	}{
+		{"13800000000", "", true},
+		{"", "user", true},
+		{"13900000000", "user", true},
+		{"13900000000", "other", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := a.Contains(tt.mobile, tt.userID); got != tt.want {
+			t.Errorf("Contains(%q, %q) = %v, want %v", tt.mobile, tt.userID, got, tt.want)
+		}
+	}
+}
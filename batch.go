@@ -0,0 +1,123 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IndexedError 批量发送中的一条失败记录
+type IndexedError struct {
+	Index int
+	Msg   Msg
+	Err   error
+}
+
+func (e IndexedError) Error() string {
+	return fmt.Sprintf("dingtalk: failed to send msg %d: %v", e.Index, e.Err)
+}
+
+func (e IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// MultiSendError 批量发送错误，包含每条发送失败的消息
+type MultiSendError struct {
+	Errors []IndexedError
+}
+
+func (e *MultiSendError) Error() string {
+	errs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err.Error()
+	}
+	return strings.Join(errs, "\n")
+}
+
+// Unwrap 返回所有失败记录，使 errors.Is 和 errors.As 可以匹配其中的错误
+func (e *MultiSendError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Failed 返回所有发送失败的消息的索引
+func (e *MultiSendError) Failed() []int {
+	indices := make([]int, len(e.Errors))
+	for i, err := range e.Errors {
+		indices[i] = err.Index
+	}
+	return indices
+}
+
+// add 添加一条失败记录
+func (e *MultiSendError) add(index int, msg Msg, err error) {
+	e.Errors = append(e.Errors, IndexedError{Index: index, Msg: msg, Err: err})
+}
+
+// err 存在失败记录时返回自身，否则返回空
+func (e *MultiSendError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// MarkdownItem 批量发送的一条 markdown 类型消息
+type MarkdownItem struct {
+	Title string
+	Text  string
+}
+
+// BatchSendMarkdown 依次发送所有 markdown 类型消息，某条发送失败时继续发送之后的消息，存在失败时返回 *MultiSendError
+func (b *Bot) BatchSendMarkdown(ctx context.Context, items []MarkdownItem, handlers ...SendHandler) error {
+	e := &MultiSendError{}
+	for i, item := range items {
+		msg := Markdown{Title: item.Title, Text: item.Text}
+		if err := b.SendWithContext(ctx, msg, handlers...); err != nil {
+			e.add(i, msg, err)
+		}
+	}
+	return e.err()
+}
+
+// BatchSendText 依次发送所有文本类型消息，详见 BatchSendMarkdown
+func (b *Bot) BatchSendText(ctx context.Context, contents []string, handlers ...SendHandler) error {
+	e := &MultiSendError{}
+	for i, content := range contents {
+		msg := Text{Content: content}
+		if err := b.SendWithContext(ctx, msg, handlers...); err != nil {
+			e.add(i, msg, err)
+		}
+	}
+	return e.err()
+}
+
+// SendBatch 依次发送所有消息，每条消息发送后等待 delay 再发送下一条，最后一条之后不等待，
+// 某条发送失败时继续发送之后的消息。等待期间上下文取消时停止发送，尚未发送的消息均记为失败，存在失败时返回 *MultiSendError
+func (b *Bot) SendBatch(ctx context.Context, msgs []Msg, delay time.Duration, handlers ...SendHandler) error {
+	e := &MultiSendError{}
+	for i, msg := range msgs {
+		if err := b.SendWithContext(ctx, msg, handlers...); err != nil {
+			e.add(i, msg, err)
+		}
+		if i == len(msgs)-1 || delay <= 0 {
+			continue
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err := fmt.Errorf("dingtalk: batch cancelled: %w", ctx.Err())
+			for j := i + 1; j < len(msgs); j++ {
+				e.add(j, msgs[j], err)
+			}
+			return e.err()
+		case <-timer.C:
+		}
+	}
+	return e.err()
+}
@@ -0,0 +1,123 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchSendMarkdown(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(rejectBad(srv))
+	items := []MarkdownItem{{"a", "first"}, {"b", "bad"}, {"c", "third"}}
+
+	err := srv.Bot().BatchSendMarkdown(context.Background(), items)
+	var e *MultiSendError
+	if !errors.As(err, &e) || !reflect.DeepEqual(e.Failed(), []int{1}) {
+		t.Fatalf("BatchSendMarkdown() = %v, want only item 1 failed", err)
+	}
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("BatchSendMarkdown() = %v, want ErrInvalidToken", err)
+	}
+	if got := e.Errors[0].Msg; got != (Markdown{Title: "b", Text: "bad"}) {
+		t.Fatalf("failed msg = %v", got)
+	}
+	requests := srv.Requests()
+	if len(requests) != 3 || requests[2].JSON(t)["markdown"].(map[string]any)["text"] != "third" {
+		t.Fatalf("server received %d requests, want all 3 sent", len(requests))
+	}
+}
+
+func TestBatchSendText(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(rejectBad(srv))
+	bot := srv.Bot()
+
+	if err := bot.BatchSendText(context.Background(), []string{"ok", "fine"}); err != nil {
+		t.Fatalf("BatchSendText() = %v, want nil", err)
+	}
+	err := bot.BatchSendText(context.Background(), []string{"bad", "ok", "bad too"}, AtAll)
+	var e *MultiSendError
+	if !errors.As(err, &e) || !reflect.DeepEqual(e.Failed(), []int{0, 2}) {
+		t.Fatalf("BatchSendText() = %v, want items 0 and 2 failed", err)
+	}
+	if n := len(srv.Requests()); n != 5 {
+		t.Fatalf("server received %d requests, want 5", n)
+	}
+	if at := srv.Last(t).JSON(t)["at"].(map[string]any); at["isAtAll"] != true {
+		t.Fatal("handlers were not applied to every message")
+	}
+}
+
+func TestMultiSendError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(rejectBad(srv))
+	msgs := []Msg{Text{Content: "ok"}, Text{Content: "bad1"}, Text{Content: "ok"}, Markdown{Title: "t", Text: "bad3"}}
+
+	err := srv.Bot().SendBatch(context.Background(), msgs, 0)
+	var e *MultiSendError
+	if !errors.As(err, &e) || !reflect.DeepEqual(e.Failed(), []int{1, 3}) {
+		t.Fatalf("SendBatch() = %v, want items 1 and 3 failed", err)
+	}
+
+	var indexed IndexedError
+	if !errors.As(err, &indexed) || indexed.Index != 1 || indexed.Msg != msgs[1] {
+		t.Fatalf("errors.As(IndexedError) = %+v, want index 1", indexed)
+	}
+	for i, ie := range e.Unwrap() {
+		if !errors.As(ie, &indexed) || indexed.Index != e.Failed()[i] || !errors.Is(indexed, ErrInvalidToken) {
+			t.Errorf("Unwrap()[%d] = %v", i, ie)
+		}
+	}
+	var sendErr SendError
+	if !errors.As(e.Errors[1], &sendErr) || sendErr.API.Msg != msgs[3] {
+		t.Fatalf("Errors[1] does not wrap the SendError of msg 3: %v", e.Errors[1])
+	}
+	if want := "dingtalk: failed to send msg 1: "; !strings.HasPrefix(err.Error(), want) || strings.Count(err.Error(), "\n") != 1 {
+		t.Fatalf("Error() = %q", err)
+	}
+	if err := srv.Bot().SendBatch(context.Background(), msgs[:1], 0); err != nil {
+		t.Fatalf("SendBatch() without failures = %v, want nil", err)
+	}
+}
+
+func TestSendBatchDelay(t *testing.T) {
+	srv := newTestServer(t)
+	msgs := []Msg{Text{Content: "1"}, Text{Content: "2"}, Text{Content: "3"}}
+	const delay = 30 * time.Millisecond
+
+	start := time.Now()
+	if err := srv.Bot().SendBatch(context.Background(), msgs, delay); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*delay || elapsed >= 3*delay+time.Second {
+		t.Fatalf("SendBatch() took %s, want two delays of %s", elapsed, delay)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Fatalf("server received %d requests, want 3", n)
+	}
+}
+
+func TestSendBatchCancel(t *testing.T) {
+	srv := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bot := srv.Bot()
+	bot.OnAfterSend = func(context.Context, *Send, *SendResponse, error) { cancel() }
+	msgs := []Msg{Text{Content: "1"}, Text{Content: "2"}, Text{Content: "3"}}
+
+	err := bot.SendBatch(ctx, msgs, time.Hour)
+	var e *MultiSendError
+	if !errors.As(err, &e) || !reflect.DeepEqual(e.Failed(), []int{1, 2}) {
+		t.Fatalf("SendBatch() = %v, want the remaining items 1 and 2 failed", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendBatch() = %v, want context.Canceled", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+}
@@ -2,10 +2,19 @@ package dingtalk
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 // Bot 钉钉机器人
@@ -28,18 +37,203 @@ type Bot struct {
 	// 每分钟发送消息限制量，平台规定每分钟最多发送 20 条消息。如果超过限制，会限流至下一分钟零秒时刻，值为零则不限流
 	Limit int `json:"limit" yaml:"limit" toml:"limit" long:"limit"`
 
+	// 发送群消息的接口地址，为空时使用 DefaultWebhook ，例如通过代理转发请求时设置，处理器 Webhook 优先于该值
+	BaseURL string `json:"baseURL" yaml:"baseURL" toml:"baseURL" long:"baseURL"`
+
+	// 请求体长度上限，单位是字节，值不为正时使用 DefaultMaxContentLen
+	MaxContentLen int `json:"maxContentLen" yaml:"maxContentLen" toml:"maxContentLen" long:"maxContentLen"`
+
+	// 发送记录容量，通过 EnableHistory 开启发送记录后生效，值不为正时使用 DefaultHistoryCap
+	HistoryCap int `json:"historyCap" yaml:"historyCap" toml:"historyCap" long:"historyCap"`
+
+	// 凭证解析器，不为空时每次发送前调用以获取凭证，优先于 Token ，但上下文中通过 WithToken 携带的凭证优先于解析器
+	TokenResolver func(ctx context.Context) (string, error) `json:"-" yaml:"-" toml:"-"`
+
+	// 安全密钥解析器，不为空时每次发送前调用以获取安全密钥，优先于 Secret ，但上下文中通过 WithSecret 携带的安全密钥优先于解析器
+	SecretResolver func(ctx context.Context) (string, error) `json:"-" yaml:"-" toml:"-"`
+
+	// 默认@配置，消息类型支持@人时会在处理器执行前应用，处理器 AtMobile 和 AtUserID 会在此基础上追加，可通过 AtClear 处理器清除
+	DefaultAt At `json:"defaultAt" yaml:"defaultAt" toml:"defaultAt"`
+
+	// 是否在执行处理器前深拷贝请求，开启后处理器修改消息中的切片不会影响调用方传入的消息
+	ImmutableSend bool `json:"-" yaml:"-" toml:"-"`
+
+	// 是否在发送前使用 MarkdownSanitizer 转义文本类型和 markdown 类型消息的所有字符串字段，只转义调用方传入的消息，在消息转换函数和添加关键词之前进行
+	AutoSanitize bool `json:"-" yaml:"-" toml:"-"`
+
+	// 默认处理器，每次发送时在调用方传入的处理器之前执行，例如 QuietHours
+	DefaultHandlers []SendHandler `json:"-" yaml:"-" toml:"-"`
+
+	// 消息模板，可通过 NewTemplate 、 ParseDir 等方法注册，模板名一般为 StructName.FieldName 的形式
+	Template *template.Template `json:"-" yaml:"-" toml:"-"`
+
+	// 自定义关键词添加策略，不为空时替代内置的添加逻辑
+	KeywordInjector KeywordInjector `json:"-" yaml:"-" toml:"-"`
+
+	// 是否记录关键词自动添加的情况，开启后每次添加都会在 KeywordLog 中追加一条记录
+	LogKeywordInjections bool `json:"-" yaml:"-" toml:"-"`
+
+	// 关键词自动添加记录，应在 LogKeywordInjections 为假或没有并发发送时读取
+	KeywordLog []KeywordInjection `json:"-" yaml:"-" toml:"-"`
+
+	// 结构化日志记录器，不为空时每次发送消息后都会记录一条日志，为空时若环境变量 DINGTALK_DEBUG=1 则使用 slog.Default()
+	SlogLogger *slog.Logger `json:"-" yaml:"-" toml:"-"`
+
+	// 链路追踪器
+	Tracer trace.Tracer `json:"-" yaml:"-" toml:"-"`
+
+	// 设置了链路追踪器时，是否在消息正文末尾添加上下文中的 traceID/spanID
+	InjectTraceInFooter bool `json:"-" yaml:"-" toml:"-"`
+
+	// 是否为每次发送创建链路追踪的跨度，通过 TraceAll 开启
+	traceSends bool
+
+	// 通过 PauseUntil 暂停期间的发送行为，默认阻塞直到暂停结束
+	PauseBehavior PauseBehavior `json:"-" yaml:"-" toml:"-"`
+
+	// 熔断器，连续发送失败达到阈值后暂停发送
+	CircuitBreaker *CircuitBreaker `json:"-" yaml:"-" toml:"-"`
+
+	// 发送前后调用的回调函数，通过 WithCallbacks 设置
+	Callbacks BotCallbacks `json:"-" yaml:"-" toml:"-"`
+
+	// 发送请求前调用，在 Callbacks.BeforeSend 之后调用
+	//
+	// Deprecated: 使用 Callbacks.BeforeSend
+	OnBeforeSend func(ctx context.Context, s *Send) `json:"-" yaml:"-" toml:"-"`
+
+	// 发送请求结束后调用，在 Callbacks.AfterSend 之后调用
+	//
+	// Deprecated: 使用 Callbacks.AfterSend
+	OnAfterSend func(ctx context.Context, s *Send, resp *SendResponse, err error) `json:"-" yaml:"-" toml:"-"`
+
+	// 请求拦截器，在 Callbacks.BeforeSend 之后、发出网络请求时调用，可修改请求或替换响应，调用 next 发出实际的请求
+	Interceptor func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) `json:"-" yaml:"-" toml:"-"`
+
+	// 每次发送请求结束后调用，在 OnAfterSend 之后调用
+	//
+	// Deprecated: 使用 Callbacks.AfterSend
+	OnSent func(api *Send, latency time.Duration, err error) `json:"-" yaml:"-" toml:"-"`
+
+	// 后台发送失败时调用，例如 SendAfter 、 SendEvery 和 SendOnChange 中的发送，其中的恐慌会被恢复并记录
+	OnError func(msg Msg, err error) `json:"-" yaml:"-" toml:"-"`
+
+	// 发送请求结束后调用的内部钩子函数，通过 AddAfterSendHook 注册，例如 Prometheus 指标收集器
+	afterSendHooks []AfterSendFunc
+
+	// 发送请求使用的客户端，通过 SetHTTPClient 设置
+	httpClient *http.Client
+
 	// 限流器，发送请求前会读取其中的值，如果通道为空则认为超过发送消息限制量
 	limiter chan struct{}
 
+	// 令牌桶限流器，通过 RateLimit 设置
+	rateLimiter *rate.Limiter
+
+	// 消息去重缓存，通过 SuppressDuplicates 开启
+	dedup *dedupCache
+
+	// 发送记录，通过 EnableHistory 开启
+	history *sentHistory
+
+	// 暂停截止时间，通过 PauseUntil 设置
+	pausedUntil time.Time
+
+	// 暂停截止时间变化或 Resume 时关闭，用于唤醒阻塞的发送
+	resumed chan struct{}
+
+	// 消息转换函数，通过 WrapMsg 注册
+	wrappers []func(Msg) Msg
+
+	// 调试输出，通过 DebugAll 设置
+	debugAll io.Writer
+
+	// 单次调试抓取，通过 Debug 注册
+	debugNext *debugCapture
+
+	// 配置文件监听器，通过 WatchConfig 开启
+	watcher *configWatcher
+
+	// 发送统计，通过 Metrics 获取
+	metrics BotMetrics
+
+	// 是否已通过 Shutdown 关闭
+	closed atomic.Bool
+
+	keywordMu sync.Mutex
+
+	mu sync.RWMutex
+
 	once sync.Once
 }
 
+// keywords 返回当前关键词切片，修改关键词的方法总是创建新切片，因此返回值可以安全地读取
+func (b *Bot) keywords() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Keywords
+}
+
+// SetKeywords 替换全部关键词
+func (b *Bot) SetKeywords(kws ...string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.Keywords = append([]string(nil), kws...)
+	b.mu.Unlock()
+}
+
+// AppendKeywords 追加关键词
+func (b *Bot) AppendKeywords(kws ...string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.Keywords = append(append(make([]string, 0, len(b.Keywords)+len(kws)), b.Keywords...), kws...)
+	b.mu.Unlock()
+}
+
+// AddKeyword 追加一个关键词
+func (b *Bot) AddKeyword(kw string) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.AppendKeywords(kw)
+	return b
+}
+
+// RemoveKeyword 移除所有与 kw 相同的关键词
+func (b *Bot) RemoveKeyword(kw string) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	keywords := make([]string, 0, len(b.Keywords))
+	for _, keyword := range b.Keywords {
+		if keyword != kw {
+			keywords = append(keywords, keyword)
+		}
+	}
+	b.Keywords = keywords
+	return b
+}
+
 // ContainsAnyKeyword 检测字符串是否包含任意一个关键词，关键词切片为空也返回真
 func (b *Bot) ContainsAnyKeyword(text string) bool {
-	if len(b.Keywords) == 0 {
+	if b == nil {
+		return false
+	}
+	return containsAnyKeyword(b.keywords(), text)
+}
+
+// containsAnyKeyword 检测字符串是否包含任意一个关键词，关键词切片为空也返回真
+func containsAnyKeyword(keywords []string, text string) bool {
+	if len(keywords) == 0 {
 		return true
 	}
-	for _, keyword := range b.Keywords {
+	for _, keyword := range keywords {
 		if keyword == "" {
 			continue
 		}
@@ -50,6 +244,71 @@ func (b *Bot) ContainsAnyKeyword(text string) bool {
 	return false
 }
 
+// injectKeyword 当内置类型消息的文本中不包含任何一个关键词时，在文本末尾添加第一个关键词，设置了 KeywordInjector 时使用自定义策略，
+// log 为假时不记录至 KeywordLog
+func (b *Bot) injectKeyword(msg Msg, log bool) Msg {
+	record := b.logKeywordInjection
+	if !log {
+		record = discardKeywordInjection
+	}
+	keywords := b.keywords()
+	if injector := b.KeywordInjector; injector != nil {
+		return injectCustomKeyword(injector, keywords, msg, record)
+	}
+	if len(keywords) == 0 {
+		return msg
+	}
+	keyword := keywords[0]
+	switch m := msg.(type) {
+	case Text:
+		if !containsAnyKeyword(keywords, m.Content) {
+			m.Content += keyword
+			record(keyword, m.Type(), "Content")
+		}
+		return m
+	case Link:
+		if !containsAnyKeyword(keywords, m.Title) && !containsAnyKeyword(keywords, m.Text) {
+			m.Text += keyword
+			record(keyword, m.Type(), "Text")
+		}
+		return m
+	case Markdown:
+		if !containsAnyKeyword(keywords, m.Title) && !containsAnyKeyword(keywords, m.Text) {
+			m.Text += keyword
+			record(keyword, m.Type(), "Text")
+		}
+		return m
+	case ActionCard:
+		if !containsAnyKeyword(keywords, m.Title) && !containsAnyKeyword(keywords, m.Text) {
+			m.Text += keyword
+			record(keyword, m.Type(), "Text")
+		}
+		return m
+	case ActionsCard:
+		if !containsAnyKeyword(keywords, m.Title) && !containsAnyKeyword(keywords, m.Text) {
+			m.Text += keyword
+			record(keyword, m.Type(), "Text")
+		}
+		return m
+	case FeedCard:
+		if len(m.Links) == 0 {
+			return m
+		}
+		for _, link := range m.Links {
+			if containsAnyKeyword(keywords, link.Title) {
+				return m
+			}
+		}
+		// 拷贝一份内容，避免修改调用者的切片
+		m.Links = append([]FeedCardLink(nil), m.Links...)
+		m.Links[len(m.Links)-1].Title += keyword
+		record(keyword, m.Type(), "Links.Title")
+		return m
+	default:
+		return msg
+	}
+}
+
 // reset 用于重置通道，每个分钟零秒时刻会清空通道，再根据限制量填入空结构体对象
 func (b *Bot) reset() {
 	for {
@@ -92,8 +351,109 @@ func (b *Bot) wait() <-chan struct{} {
 	return b.limiter
 }
 
+// NewSend 创建与 SendWithContext 发送时相同的请求，包括转换消息、添加关键词、应用默认@配置和执行所有处理器，但不发送请求，
+// 可用于检查或序列化请求，注意加签的时间戳在创建时生成，过久之后再发送会失效
+func (b *Bot) NewSend(ctx context.Context, msg Msg, handlers ...SendHandler) (*Send, error) {
+	if b == nil {
+		return nil, ErrNilBot
+	}
+	return b.newSend(ctx, msg, handlers)
+}
+
+// prepareMsg 依次应用自动转义、消息转换函数、链路追踪信息和关键词，得到处理器执行前的消息，
+// 只有调用方传入的消息会被转义，转换函数添加的标记和关键词都不会被转义
+func (b *Bot) prepareMsg(ctx context.Context, msg Msg, log bool) (Msg, error) {
+	if b.AutoSanitize {
+		msg = sanitizeMsg(msg)
+	}
+	msg, err := b.wrapMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	return b.injectKeyword(b.injectTraceFooter(ctx, msg), log), nil
+}
+
+// newSend 创建请求，注入链路信息、关键词并执行处理器，得到最终要发送的请求
+func (b *Bot) newSend(ctx context.Context, msg Msg, handlers []SendHandler) (*Send, error) {
+	b.mu.RLock()
+	token, secret, baseURL := b.Token, b.Secret, b.BaseURL
+	b.mu.RUnlock()
+	var err error
+	if t, ok := tokenFromContext(ctx); ok {
+		token = t
+	} else if b.TokenResolver != nil {
+		token, err = b.TokenResolver(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dingtalk: failed to resolve token: %w", err)
+		}
+	}
+	if s, ok := secretFromContext(ctx); ok {
+		secret = s
+	} else if b.SecretResolver != nil {
+		secret, err = b.SecretResolver(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dingtalk: failed to resolve secret: %w", err)
+		}
+	}
+	msg, err = b.prepareMsg(ctx, msg, true)
+	if err != nil {
+		return nil, err
+	}
+	api := &Send{Msg: msg, AccessToken: token, Webhook: baseURL, ctx: ctx}
+	if msg != nil && msg.Type().AtSupported() {
+		api.At = b.defaultAt()
+	}
+	if b.ImmutableSend {
+		api = api.Clone()
+	}
+	for _, handler := range b.DefaultHandlers {
+		if err = handler(api); err != nil {
+			return nil, err
+		}
+	}
+	for _, handler := range handlers {
+		if err = handler(api); err != nil {
+			return nil, err
+		}
+	}
+	if secret != "" {
+		if err = Secret(secret)(api); err != nil {
+			return nil, err
+		}
+	}
+	return api, nil
+}
+
+// Serialize 生成发送消息时的请求体，但不发送请求
+func (b *Bot) Serialize(ctx context.Context, msg Msg, handlers ...SendHandler) ([]byte, error) {
+	if b == nil {
+		return nil, ErrNilBot
+	}
+	api, err := b.newSend(ctx, msg, handlers)
+	if err != nil {
+		return nil, err
+	}
+	return api.MarshalJSON()
+}
+
 // SendWithContext 携带上下文发送消息
-func (b *Bot) SendWithContext(ctx context.Context, msg Msg, handlers ...SendHandler) error {
+func (b *Bot) SendWithContext(ctx context.Context, msg Msg, handlers ...SendHandler) (err error) {
+	if b == nil {
+		return ErrNilBot
+	}
+	if b.closed.Load() {
+		return ErrClosedBot
+	}
+	// 钩子函数收到的是调用方传入的上下文，不包括之后创建的链路追踪和超时
+	parent := ctx
+	if tracer := b.Tracer; tracer != nil && b.traceSends {
+		var span trace.Span
+		ctx, span = b.startSpan(ctx, tracer, msg)
+		defer func() { endSpan(span, err) }()
+	}
+	if err = b.waitPause(ctx); err != nil {
+		return err
+	}
 	if b.Limit > 0 {
 		select {
 		case <-b.wait():
@@ -101,18 +461,111 @@ func (b *Bot) SendWithContext(ctx context.Context, msg Msg, handlers ...SendHand
 			return fmt.Errorf("dingtalk: sending rate limit exceeded: %d/min", b.Limit)
 		}
 	}
-	if b.Timeout > 0 {
+	if limiter := b.rateLimiter; limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("dingtalk: failed to wait for rate limiter: %w", err)
+		}
+	}
+	b.mu.RLock()
+	timeout := b.Timeout
+	b.mu.RUnlock()
+	if timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
-	if b.Secret != "" {
-		handlers = append(handlers, Secret(b.Secret))
+	api, err := b.newSend(ctx, msg, handlers)
+	if err != nil {
+		return err
+	}
+	if !api.At.IsZero() && api.Msg != nil && !api.Msg.Type().AtSupported() {
+		return fmt.Errorf("%w: %s", ErrAtNotSupported, api.Msg.Type())
+	}
+	release, err := b.reserve(api)
+	if err != nil {
+		return err
+	}
+	breaker := b.CircuitBreaker
+	if breaker != nil {
+		if err = breaker.Allow(); err != nil {
+			release()
+			return err
+		}
+	}
+	if err = api.waitLimiters(ctx); err != nil {
+		if breaker != nil {
+			breaker.abort()
+		}
+		release()
+		return err
+	}
+	if hook := b.Callbacks.BeforeSend; hook != nil {
+		b.callHook("Callbacks.BeforeSend", func() { hook(parent, api) })
+	}
+	if hook := b.OnBeforeSend; hook != nil {
+		b.callHook("OnBeforeSend", func() { hook(parent, api) })
+	}
+	api.runBeforeSend(parent, b.logger())
+	start := time.Now()
+	session := b.session(api)
+	if w := b.debugWriter(); w != nil {
+		session = withTransport(session, func(base http.RoundTripper) http.RoundTripper {
+			return debugTransport{base: base, w: w}
+		})
+	}
+	if interceptor := b.Interceptor; interceptor != nil {
+		session = withTransport(session, func(base http.RoundTripper) http.RoundTripper {
+			return interceptTransport{base: base, interceptor: interceptor}
+		})
+	}
+	r, err := postSendWithDuplicates(ctx, session, api)
+	latency := time.Since(start)
+	resp := &r
+	if err != nil {
+		resp = nil
+	}
+	if hook := b.Callbacks.AfterSend; hook != nil {
+		b.callHook("Callbacks.AfterSend", func() { hook(parent, api, resp, latency, err) })
+	}
+	b.runAfterSendHooks(parent, api, resp, latency, err)
+	if hook := b.OnAfterSend; hook != nil {
+		b.callHook("OnAfterSend", func() { hook(parent, api, resp, err) })
+	}
+	api.runAfterSend(parent, b.logger(), r, err)
+	b.metrics.record(latency, err)
+	if b.traceSends {
+		recordSpan(ctx, r, latency)
+	}
+	b.logSend(ctx, api, r, latency, err)
+	if h := b.history; h != nil {
+		h.add(SentRecord{Msg: api.Msg, At: api.At, Timestamp: start, Latency: latency, Err: err, TraceID: r.TraceID})
+	}
+	if b.OnSent != nil {
+		b.OnSent(api, latency, err)
+	}
+	if breaker != nil {
+		breaker.Done(err)
+	}
+	if err != nil {
+		release()
 	}
-	_, err := PostSendWithContext(ctx, b.Token, msg, handlers...)
 	return err
 }
 
+// ErrAtNotSupported 消息类型不支持@人
+var ErrAtNotSupported = errors.New("dingtalk: msg type does not support at")
+
+// SendIfAtAllowed 携带上下文发送消息，消息类型支持@人时才应用 at ，否则静默忽略
+func (b *Bot) SendIfAtAllowed(ctx context.Context, msg Msg, at At, handlers ...SendHandler) error {
+	if msg != nil && msg.Type().AtSupported() {
+		handlers = append(handlers, func(s *Send) error {
+			s.At = at
+			return nil
+		})
+	}
+	return b.SendWithContext(ctx, msg, handlers...)
+}
+
 // Send 发送消息
 func (b *Bot) Send(msg Msg, handlers ...SendHandler) error {
 	return b.SendWithContext(context.Background(), msg, handlers...)
@@ -120,9 +573,6 @@ func (b *Bot) Send(msg Msg, handlers ...SendHandler) error {
 
 // SendTextWithContext 携带上下文发送文本类型消息
 func (b *Bot) SendTextWithContext(ctx context.Context, content string, handlers ...SendHandler) error {
-	if !b.ContainsAnyKeyword(content) {
-		content += b.Keywords[0]
-	}
 	return b.SendWithContext(ctx, Text{Content: content}, handlers...)
 }
 
@@ -133,9 +583,6 @@ func (b *Bot) SendText(content string, handlers ...SendHandler) error {
 
 // SendLinkWithContext 携带上下文发送链接类型消息
 func (b *Bot) SendLinkWithContext(ctx context.Context, title, text, msgURL, picURL string, handlers ...SendHandler) error {
-	if !b.ContainsAnyKeyword(title) && !b.ContainsAnyKeyword(text) {
-		text += b.Keywords[0]
-	}
 	return b.SendWithContext(ctx, Link{Title: title, Text: text, MessageURL: msgURL, PicURL: picURL}, handlers...)
 }
 
@@ -146,9 +593,6 @@ func (b *Bot) SendLink(title, text, msgURL, picURL string, handlers ...SendHandl
 
 // SendMarkdownWithContext 携带上下文发送 markdown 类型消息
 func (b *Bot) SendMarkdownWithContext(ctx context.Context, title, text string, handlers ...SendHandler) error {
-	if !b.ContainsAnyKeyword(title) && !b.ContainsAnyKeyword(text) {
-		text += b.Keywords[0]
-	}
 	return b.SendWithContext(ctx, Markdown{Title: title, Text: text}, handlers...)
 }
 
@@ -159,9 +603,6 @@ func (b *Bot) SendMarkdown(title, text string, handlers ...SendHandler) error {
 
 // SendActionCardWithContext 携带上下文发送整体跳转 actionCard 类型消息
 func (b *Bot) SendActionCardWithContext(ctx context.Context, title, text, singleTitle, singleURL string, handlers ...SendHandler) error {
-	if !b.ContainsAnyKeyword(title) && !b.ContainsAnyKeyword(text) {
-		text += b.Keywords[0]
-	}
 	return b.SendWithContext(ctx, ActionCard{Title: title, Text: text, SingleTitle: singleTitle, SingleURL: singleURL}, handlers...)
 }
 
@@ -172,9 +613,6 @@ func (b *Bot) SendActionCard(title, text, singleTitle, singleURL string, handler
 
 // SendActionsCardWithContext 携带上下文发送独立跳转 actionCard 类型消息
 func (b *Bot) SendActionsCardWithContext(ctx context.Context, title, text string, btns []ActionCardBtn, handlers ...SendHandler) error {
-	if !b.ContainsAnyKeyword(title) && !b.ContainsAnyKeyword(text) {
-		text += b.Keywords[0]
-	}
 	return b.SendWithContext(ctx, ActionsCard{Title: title, Text: text, Btns: btns}, handlers...)
 }
 
@@ -183,20 +621,41 @@ func (b *Bot) SendActionsCard(title, text string, btns []ActionCardBtn, handlers
 	return b.SendActionsCardWithContext(context.Background(), title, text, btns, handlers...)
 }
 
+// ErrOddPairCount 按钮的标题和跳转链接没有成对出现
+var ErrOddPairCount = errors.New("dingtalk: odd number of button title/url pairs")
+
+// pairsToBtns 将交替出现的标题和跳转链接转换为按钮列表
+func pairsToBtns(pairs []string) ([]ActionCardBtn, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("%w: %d", ErrOddPairCount, len(pairs))
+	}
+	btns := make([]ActionCardBtn, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		btns = append(btns, ActionCardBtn{Title: pairs[i], ActionURL: pairs[i+1]})
+	}
+	return btns, nil
+}
+
+// SendActionPairs 携带上下文发送按钮竖直排列的独立跳转 actionCard 类型消息，pairs 为交替出现的按钮标题和跳转链接
+func (b *Bot) SendActionPairs(ctx context.Context, title, text string, pairs ...string) error {
+	btns, err := pairsToBtns(pairs)
+	if err != nil {
+		return err
+	}
+	return b.SendWithContext(ctx, ActionsCard{Title: title, Text: text, Btns: btns, BtnOrientation: BtnOrientationVertical})
+}
+
+// SendActionPairsH 携带上下文发送按钮横向排列的独立跳转 actionCard 类型消息，详见 SendActionPairs
+func (b *Bot) SendActionPairsH(ctx context.Context, title, text string, pairs ...string) error {
+	btns, err := pairsToBtns(pairs)
+	if err != nil {
+		return err
+	}
+	return b.SendWithContext(ctx, ActionsCard{Title: title, Text: text, Btns: btns, BtnOrientation: BtnOrientationHorizontal})
+}
+
 // SendFeedCardWithContext 携带上下文发送 feedCard 类型消息
 func (b *Bot) SendFeedCardWithContext(ctx context.Context, links []FeedCardLink, handlers ...SendHandler) error {
-	if len(b.Keywords) != 0 {
-		var hasKeyword bool
-		for i := range links {
-			if b.ContainsAnyKeyword(links[i].Title) {
-				hasKeyword = true
-				break
-			}
-		}
-		if !hasKeyword {
-			links[len(links)-1].Title += b.Keywords[0]
-		}
-	}
 	return b.SendWithContext(ctx, FeedCard{Links: links}, handlers...)
 }
 
@@ -204,3 +663,12 @@ func (b *Bot) SendFeedCardWithContext(ctx context.Context, links []FeedCardLink,
 func (b *Bot) SendFeedCard(links []FeedCardLink, handlers ...SendHandler) error {
 	return b.SendFeedCardWithContext(context.Background(), links, handlers...)
 }
+
+// SendFeedCardFromURLs 携带上下文发送由标题、跳转链接和图片 URL 切片构建的 feedCard 类型消息，详见 BuildFeedCard
+func (b *Bot) SendFeedCardFromURLs(ctx context.Context, titles, urls, pics []string, handlers ...SendHandler) error {
+	f, err := BuildFeedCard(titles, urls, pics)
+	if err != nil {
+		return err
+	}
+	return b.SendFeedCardWithContext(ctx, f.Links, handlers...)
+}
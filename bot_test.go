@@ -0,0 +1,88 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSerialize(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Secret = "SECsecret"
+	bot.Keywords = []string{"告警"}
+	ctx := context.Background()
+	for _, msg := range []Msg{
+		Text{Content: "disk full"},
+		Markdown{Title: "disk", Text: "### disk full"},
+		ActionsCard{Title: "t", Text: "x"}.AddButton("a", "https://example.com/a"),
+		FeedCard{Links: []FeedCardLink{{Title: "a", MessageURL: "https://example.com/a"}}},
+	} {
+		handlers := []SendHandler{UUID("uuid")}
+		if msg.Type().AtSupported() {
+			handlers = append(handlers, AtUserID("user1"))
+		}
+		data, err := bot.Serialize(ctx, msg, handlers...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = bot.SendWithContext(ctx, msg, handlers...); err != nil {
+			t.Fatal(err)
+		}
+		if body := srv.Last(t).Body; !bytes.Equal(data, body) {
+			t.Errorf("%s: Serialize() = %s, server received %s", msg.Type(), data, body)
+		}
+	}
+	if n := len(srv.Requests()); n != 4 {
+		t.Fatalf("server received %d requests, want 4, Serialize should not send", n)
+	}
+}
+
+func TestTokenResolver(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	const resolved = "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	type ctxKey struct{}
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "tenant"), time.Minute)
+	defer cancel()
+	bot.TokenResolver = func(rctx context.Context) (string, error) {
+		if rctx.Value(ctxKey{}) != "tenant" {
+			t.Error("TokenResolver called without the send context")
+		}
+		if _, ok := rctx.Deadline(); !ok {
+			t.Error("TokenResolver context has no deadline")
+		}
+		return resolved, nil
+	}
+	bot.SecretResolver = func(context.Context) (string, error) { return "SECresolved", nil }
+	if err := bot.SendWithContext(ctx, Text{Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	query := srv.Last(t).Query
+	if got := query.Get("access_token"); got != resolved {
+		t.Fatalf("access_token = %q, want resolved token", got)
+	}
+	if query.Get("sign") == "" || query.Get("timestamp") == "" {
+		t.Fatalf("query = %v, want sign from resolved secret", query)
+	}
+}
+
+func TestTokenResolverError(t *testing.T) {
+	srv := newTestServer(t)
+	errResolve := errors.New("secret manager unavailable")
+	bot := srv.Bot()
+	bot.TokenResolver = func(context.Context) (string, error) { return "", errResolve }
+	if err := bot.SendWithContext(context.Background(), Text{Content: "hello"}); !errors.Is(err, errResolve) {
+		t.Fatalf("SendWithContext() error = %v, want resolver error", err)
+	}
+	bot = srv.Bot()
+	bot.SecretResolver = func(context.Context) (string, error) { return "", errResolve }
+	if err := bot.SendWithContext(context.Background(), Text{Content: "hello"}); !errors.Is(err, errResolve) {
+		t.Fatalf("SendWithContext() error = %v, want resolver error", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("server received %d requests, want 0", n)
+	}
+}
@@ -0,0 +1,44 @@
+package dingtalk
+
+import (
+	"context"
+	"sync"
+)
+
+// BroadcastAll 按顺序发送所有消息，返回的错误与消息一一对应，发送成功的位置为空
+func (b *Bot) BroadcastAll(ctx context.Context, msgs []Msg, handlers ...SendHandler) []error {
+	errs := make([]error, len(msgs))
+	for i, msg := range msgs {
+		errs[i] = b.SendWithContext(ctx, msg, handlers...)
+	}
+	return errs
+}
+
+// BroadcastAll 向机器人组中的所有机器人并发发送所有消息，每个机器人内部按顺序发送，
+// 空的机器人会被跳过，返回每个机器人发送各条消息的错误，详见 Bot.BroadcastAll
+func (g *BotGroup) BroadcastAll(ctx context.Context, msgs []Msg) map[*Bot][]error {
+	g.mu.RLock()
+	bots := g.Bots
+	g.mu.RUnlock()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	result := make(map[*Bot][]error, len(bots))
+	for _, bot := range bots {
+		if bot == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(bot *Bot) {
+			defer wg.Done()
+			errs := bot.BroadcastAll(ctx, msgs)
+			mu.Lock()
+			result[bot] = errs
+			mu.Unlock()
+		}(bot)
+	}
+	wg.Wait()
+	return result
+}
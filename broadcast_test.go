@@ -0,0 +1,66 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// rejectBad 对内容包含 bad 的消息返回错误码
+func rejectBad(srv *testServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests := srv.Requests()
+		if bytes.Contains(requests[len(requests)-1].Body, []byte("bad")) {
+			replyError(ErrInvalidToken.Code, "rejected")(w, r)
+			return
+		}
+		replyError(0, "ok")(w, r)
+	}
+}
+
+func TestBotBroadcastAll(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(rejectBad(srv))
+	msgs := []Msg{Text{Content: "ok0"}, Text{Content: "bad1"}, Text{Content: "ok2"}, Text{Content: "bad3"}}
+	errs := srv.Bot().BroadcastAll(context.Background(), msgs)
+	if len(errs) != len(msgs) {
+		t.Fatalf("BroadcastAll() returned %d errors, want %d", len(errs), len(msgs))
+	}
+	for i, err := range errs {
+		if want := i%2 == 1; (err != nil) != want || (want && !errors.Is(err, ErrInvalidToken)) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+	var got []string
+	for _, r := range srv.Requests() {
+		got = append(got, r.JSON(t)["text"].(map[string]any)["content"].(string))
+	}
+	if len(got) != 4 || got[0] != "ok0" || got[3] != "bad3" {
+		t.Fatalf("send order = %q, want input order", got)
+	}
+}
+
+func TestBotGroupBroadcastAll(t *testing.T) {
+	good, bad := newTestServer(t), newTestServer(t)
+	bad.SetHandler(replyError(ErrInvalidToken.Code, "rejected"))
+	goodBot, badBot := good.Bot(), bad.Bot()
+	g := NewBotGroup(goodBot, nil, badBot)
+	msgs := []Msg{Text{Content: "a"}, Markdown{Title: "b", Text: "b"}}
+	result := g.BroadcastAll(context.Background(), msgs)
+	if len(result) != 2 {
+		t.Fatalf("BroadcastAll() returned %d bots, want 2", len(result))
+	}
+	for i, err := range result[goodBot] {
+		if err != nil {
+			t.Errorf("good bot errs[%d] = %v", i, err)
+		}
+	}
+	if errs := result[badBot]; len(errs) != 2 || !errors.Is(errs[0], ErrInvalidToken) || !errors.Is(errs[1], ErrInvalidToken) {
+		t.Fatalf("bad bot errs = %v", errs)
+	}
+	if n := len(good.Requests()); n != 2 {
+		t.Fatalf("good server received %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,93 @@
+package dingtalk
+
+import "slices"
+
+// WithContent 设置文本消息的内容，返回新的消息
+func (t Text) WithContent(content string) Text {
+	t.Content = content
+	return t
+}
+
+// WithTitle 设置链接消息标题，返回新的消息
+func (l Link) WithTitle(title string) Link {
+	l.Title = title
+	return l
+}
+
+// WithText 设置链接消息的内容，返回新的消息
+func (l Link) WithText(text string) Link {
+	l.Text = text
+	return l
+}
+
+// WithMessageURL 设置点击消息跳转的 URL ，返回新的消息
+func (l Link) WithMessageURL(url string) Link {
+	l.MessageURL = url
+	return l
+}
+
+// WithPicURL 设置链接消息内的图片地址，返回新的消息
+func (l Link) WithPicURL(url string) Link {
+	l.PicURL = url
+	return l
+}
+
+// WithTitle 设置消息会话列表中展示的标题，返回新的消息
+func (m Markdown) WithTitle(title string) Markdown {
+	m.Title = title
+	return m
+}
+
+// WithText 设置 markdown 类型消息的文本内容，返回新的消息
+func (m Markdown) WithText(text string) Markdown {
+	m.Text = text
+	return m
+}
+
+// WithTitle 设置消息会话列表中展示的标题，返回新的消息
+func (a ActionCard) WithTitle(title string) ActionCard {
+	a.Title = title
+	return a
+}
+
+// WithText 设置 actionCard 类型消息的正文内容，返回新的消息
+func (a ActionCard) WithText(text string) ActionCard {
+	a.Text = text
+	return a
+}
+
+// WithSingleTitle 设置按钮上显示的文本，返回新的消息
+func (a ActionCard) WithSingleTitle(title string) ActionCard {
+	a.SingleTitle = title
+	return a
+}
+
+// WithSingleURL 设置点击按钮触发的 URL ，返回新的消息
+func (a ActionCard) WithSingleURL(url string) ActionCard {
+	a.SingleURL = url
+	return a
+}
+
+// WithTitle 设置消息会话列表中展示的标题，返回新的消息
+func (a ActionsCard) WithTitle(title string) ActionsCard {
+	a.Title = title
+	return a
+}
+
+// WithText 设置 actionCard 类型消息的正文内容，返回新的消息
+func (a ActionsCard) WithText(text string) ActionsCard {
+	a.Text = text
+	return a
+}
+
+// WithBtns 替换按钮列表，返回新的消息
+func (a ActionsCard) WithBtns(btns ...ActionCardBtn) ActionsCard {
+	a.Btns = slices.Clone(btns)
+	return a
+}
+
+// WithLinks 替换内容列表，返回新的消息
+func (f FeedCard) WithLinks(links ...FeedCardLink) FeedCard {
+	f.Links = slices.Clone(links)
+	return f
+}
@@ -0,0 +1,47 @@
+package dingtalk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMsgBuilders(t *testing.T) {
+	btns := []ActionCardBtn{{Title: "同意", ActionURL: "https://example.com/yes"}}
+	links := feedLinks(2)
+	tests := []struct {
+		got, want Msg
+	}{
+		{Text{}.WithContent("content"), Text{Content: "content"}},
+		{
+			Link{}.WithTitle("title").WithText("text").WithMessageURL("https://example.com").WithPicURL("https://example.com/a.png"),
+			Link{Title: "title", Text: "text", MessageURL: "https://example.com", PicURL: "https://example.com/a.png"},
+		},
+		{Markdown{}.WithTitle("title").WithText("# text"), Markdown{Title: "title", Text: "# text"}},
+		{
+			ActionCard{}.WithTitle("title").WithText("text").WithSingleTitle("查看").WithSingleURL("https://example.com").WithHideAvatar(true),
+			ActionCard{Title: "title", Text: "text", SingleTitle: "查看", SingleURL: "https://example.com", HideAvatar: "1"},
+		},
+		{
+			ActionsCard{}.WithTitle("title").WithText("text").WithBtns(btns...).WithBtnOrientation(BtnOrientationHorizontal).WithHideAvatar(true),
+			ActionsCard{Title: "title", Text: "text", Btns: btns, BtnOrientation: BtnOrientationHorizontal, HideAvatar: "1"},
+		},
+		{FeedCard{}.WithLinks(links...), FeedCard{Links: links}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%T builders = %+v, want %+v", tt.want, tt.got, tt.want)
+		}
+	}
+
+	text := Text{Content: "original"}
+	if text.WithContent("changed"); text.Content != "original" {
+		t.Fatal("WithContent() modified the receiver")
+	}
+	card := ActionsCard{}.WithBtns(btns...)
+	btns[0].Title = "changed"
+	feed := FeedCard{}.WithLinks(links...)
+	links[0].Title = "changed"
+	if card.Btns[0].Title != "同意" || feed.Links[0].Title != "title0" {
+		t.Fatal("WithBtns() or WithLinks() shares the argument slice")
+	}
+}
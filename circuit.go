@@ -0,0 +1,143 @@
+package dingtalk
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 闭合，正常发送
+	CircuitOpen                         // 断开，拒绝发送
+	CircuitHalfOpen                     // 半开，允许一次试探发送
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen 熔断器处于断开状态，拒绝发送
+var ErrCircuitOpen = errors.New("dingtalk: circuit breaker is open")
+
+// CircuitBreaker 熔断器，连续失败达到阈值后断开，经过一段时间后进入半开状态并允许一次试探，试探成功则重新闭合
+type CircuitBreaker struct {
+	// 连续失败阈值
+	FailureThreshold int
+
+	// 断开后经过多久进入半开状态
+	HalfOpenAfter time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker 新建熔断器
+func NewCircuitBreaker(failureThreshold int, halfOpenAfter time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{FailureThreshold: failureThreshold, HalfOpenAfter: halfOpenAfter}
+}
+
+// Allow 判断是否允许发送，断开状态下返回 ErrCircuitOpen
+func (c *CircuitBreaker) Allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch c.state {
+	case CircuitOpen:
+		if time.Since(c.openedAt) < c.HalfOpenAfter {
+			return ErrCircuitOpen
+		}
+		c.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		// 已经有一次试探正在进行
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// Done 记录一次发送的结果
+func (c *CircuitBreaker) Done(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.state = CircuitClosed
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= c.FailureThreshold {
+		c.state = CircuitOpen
+		c.openedAt = time.Now()
+	}
+}
+
+// State 返回熔断器当前状态，断开时间超过 HalfOpenAfter 时返回半开状态
+func (c *CircuitBreaker) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= c.HalfOpenAfter {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// abort 放弃 Allow 允许但未发出的发送，半开状态下恢复为断开状态，使下一次发送重新试探，不计入失败次数
+func (c *CircuitBreaker) abort() {
+	c.mu.Lock()
+	if c.state == CircuitHalfOpen {
+		c.state = CircuitOpen
+	}
+	c.mu.Unlock()
+}
+
+// Reset 强制闭合熔断器
+func (c *CircuitBreaker) Reset() {
+	c.mu.Lock()
+	c.state = CircuitClosed
+	c.failures = 0
+	c.mu.Unlock()
+}
+
+// WithCircuitBreaker 设置熔断器
+func (b *Bot) WithCircuitBreaker(failureThreshold int, halfOpenAfter time.Duration) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.CircuitBreaker = NewCircuitBreaker(failureThreshold, halfOpenAfter)
+	return b
+}
+
+// CircuitBreakerState 返回熔断器当前状态，未设置熔断器时总是闭合状态
+func (b *Bot) CircuitBreakerState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	if b.CircuitBreaker == nil {
+		return CircuitClosed
+	}
+	return b.CircuitBreaker.State()
+}
+
+// ResetCircuitBreaker 强制闭合熔断器
+func (b *Bot) ResetCircuitBreaker() {
+	if b == nil {
+		return
+	}
+	if b.CircuitBreaker != nil {
+		b.CircuitBreaker.Reset()
+	}
+}
@@ -0,0 +1,92 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	halfOpenAfter := 50 * time.Millisecond
+	bot := srv.Bot().WithCircuitBreaker(3, halfOpenAfter)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if state := bot.CircuitBreakerState(); state != CircuitClosed {
+			t.Fatalf("state after %d failures = %s, want closed", i, state)
+		}
+		if err := bot.SendWithContext(ctx, Text{Content: "fail"}); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("send %d error = %v, want ErrInvalidToken", i, err)
+		}
+	}
+	if state := bot.CircuitBreakerState(); state != CircuitOpen {
+		t.Fatalf("state = %s, want open", state)
+	}
+	if err := bot.SendWithContext(ctx, Text{Content: "rejected"}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("send while open error = %v, want ErrCircuitOpen", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Fatalf("server received %d requests, want 3", n)
+	}
+
+	time.Sleep(halfOpenAfter)
+	if state := bot.CircuitBreakerState(); state != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open", state)
+	}
+	srv.SetHandler(nil)
+	if err := bot.SendWithContext(ctx, Text{Content: "probe"}); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if state := bot.CircuitBreakerState(); state != CircuitClosed {
+		t.Fatalf("state after probe = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerProbeFailure(t *testing.T) {
+	c := NewCircuitBreaker(1, time.Millisecond)
+	c.Done(errors.New("fail"))
+	time.Sleep(2 * time.Millisecond)
+	if err := c.Allow(); err != nil {
+		t.Fatalf("Allow() after halfOpenAfter error = %v", err)
+	}
+	if err := c.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe Allow() error = %v, want ErrCircuitOpen", err)
+	}
+	c.Done(errors.New("fail"))
+	if state := c.State(); state != CircuitOpen {
+		t.Fatalf("state after failed probe = %s, want open", state)
+	}
+}
+
+func TestCircuitBreakerProbeAbort(t *testing.T) {
+	c := NewCircuitBreaker(1, time.Millisecond)
+	c.Done(errors.New("fail"))
+	time.Sleep(2 * time.Millisecond)
+	if err := c.Allow(); err != nil {
+		t.Fatalf("Allow() after halfOpenAfter error = %v", err)
+	}
+	c.abort()
+	if err := c.Allow(); err != nil {
+		t.Fatalf("Allow() after an aborted probe error = %v, want a new probe", err)
+	}
+	if c.failures != 1 {
+		t.Fatalf("failures = %d after abort, want 1", c.failures)
+	}
+}
+
+func TestResetCircuitBreaker(t *testing.T) {
+	bot := (&Bot{}).WithCircuitBreaker(1, time.Hour)
+	bot.CircuitBreaker.Done(errors.New("fail"))
+	if state := bot.CircuitBreakerState(); state != CircuitOpen {
+		t.Fatalf("state = %s, want open", state)
+	}
+	bot.ResetCircuitBreaker()
+	if state := bot.CircuitBreakerState(); state != CircuitClosed {
+		t.Fatalf("state after reset = %s, want closed", state)
+	}
+	if state := (&Bot{}).CircuitBreakerState(); state != CircuitClosed {
+		t.Fatalf("state without breaker = %s, want closed", state)
+	}
+}
@@ -0,0 +1,67 @@
+package dingtalk
+
+import (
+	"net/http"
+
+	"github.com/Drelf2018/req"
+)
+
+// SetHTTPClient 设置发送请求使用的客户端，值为空时恢复使用默认客户端
+func (b *Bot) SetHTTPClient(client *http.Client) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.httpClient = client
+	b.mu.Unlock()
+}
+
+// HTTPClient 返回发送请求使用的客户端，未设置时返回 http.DefaultClient
+func (b *Bot) HTTPClient() *http.Client {
+	if b == nil {
+		return http.DefaultClient
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.httpClient == nil {
+		return http.DefaultClient
+	}
+	return b.httpClient
+}
+
+// session 返回发送请求时使用的会话，凭证为 TestWebhookToken 的请求总是使用进程内的模拟接口
+func (b *Bot) session(api *Send) *req.Session {
+	if api.AccessToken == TestWebhookToken {
+		return mockSession
+	}
+	b.mu.RLock()
+	client := b.httpClient
+	b.mu.RUnlock()
+	if client == nil {
+		return req.DefaultSession
+	}
+	return &req.Session{Client: *client, Header: req.DefaultSession.Header}
+}
+
+// withTransport 返回使用 wrap 包装后的传输层发送请求的会话，不会修改原会话
+func withTransport(session *req.Session, wrap func(http.RoundTripper) http.RoundTripper) *req.Session {
+	client := session.Client
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = wrap(base)
+	return &req.Session{Client: client, Header: session.Header}
+}
+
+// interceptTransport 通过 Bot.Interceptor 发送请求
+type interceptTransport struct {
+	base        http.RoundTripper
+	interceptor func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error)
+}
+
+func (t interceptTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return t.interceptor(r, t.base.RoundTrip)
+}
+
+var _ http.RoundTripper = interceptTransport{}
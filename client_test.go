@@ -0,0 +1,53 @@
+package dingtalk
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestHTTPClient(t *testing.T) {
+	bot := &Bot{}
+	if got := bot.HTTPClient(); got != http.DefaultClient {
+		t.Fatalf("HTTPClient() = %p, want http.DefaultClient", got)
+	}
+	client := &http.Client{}
+	bot.SetHTTPClient(client)
+	if got := bot.HTTPClient(); got != client {
+		t.Fatalf("HTTPClient() = %p, want %p", got, client)
+	}
+	bot.SetHTTPClient(nil)
+	if got := bot.HTTPClient(); got != http.DefaultClient {
+		t.Fatalf("HTTPClient() after reset = %p, want http.DefaultClient", got)
+	}
+	if got := (*Bot)(nil).HTTPClient(); got != http.DefaultClient {
+		t.Fatalf("nil bot HTTPClient() = %p, want http.DefaultClient", got)
+	}
+}
+
+// TestHTTPClientRace 需要使用 go test -race 运行才能发现数据竞争
+func TestHTTPClientRace(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	clients := []*http.Client{srv.Client(), {Transport: srv.Client().Transport}, nil}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			bot.SetHTTPClient(clients[i%len(clients)])
+			bot.HTTPClient()
+		}(i)
+		go func() {
+			defer wg.Done()
+			if err := bot.SendText("race"); err != nil {
+				t.Errorf("SendText() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(srv.Requests()); n != 8 {
+		t.Fatalf("server received %d requests, want 8", n)
+	}
+}
@@ -0,0 +1,39 @@
+package dingtalk
+
+import (
+	"maps"
+	"slices"
+)
+
+// CopyMsg 深拷贝内置类型的消息，包括其中的切片，其他类型的消息原样返回
+func CopyMsg(msg Msg) Msg {
+	switch m := msg.(type) {
+	case ActionsCard:
+		m.Btns = slices.Clone(m.Btns)
+		return m
+	case FeedCard:
+		m.Links = slices.Clone(m.Links)
+		return m
+	case RawMsg:
+		// 只拷贝请求体的顶层字段
+		m.Body = maps.Clone(m.Body)
+		return m
+	default:
+		// Text 、 Link 、 Markdown 和 ActionCard 只包含字符串字段，值拷贝即为深拷贝
+		return msg
+	}
+}
+
+// Clone 深拷贝请求，包括@配置中的切片和消息，处理器可以安全地修改拷贝
+func (s *Send) Clone() *Send {
+	c := *s
+	c.Msg = CopyMsg(s.Msg)
+	c.At = s.At.Clone()
+	c.ExtraQuery = maps.Clone(s.ExtraQuery)
+	c.ExtraBody = maps.Clone(s.ExtraBody)
+	c.beforeSend = slices.Clone(s.beforeSend)
+	c.afterSend = slices.Clone(s.afterSend)
+	c.duplicates = slices.Clone(s.duplicates)
+	c.limiters = slices.Clone(s.limiters)
+	return &c
+}
@@ -0,0 +1,93 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSendClone(t *testing.T) {
+	api := &Send{
+		Msg:        ActionsCard{Title: "t", Btns: []ActionCardBtn{{Title: "a", ActionURL: "https://example.com"}}},
+		At:         At{AtMobiles: []string{"13800000000"}, AtUserIDs: []string{"user"}},
+		ExtraQuery: map[string]string{"k": "v"},
+	}
+	c := api.Clone()
+	c.At.AtMobiles[0] = "changed"
+	c.At.AtUserIDs[0] = "changed"
+	c.Msg.(ActionsCard).Btns[0].Title = "changed"
+	c.ExtraQuery["k"] = "changed"
+	c.OnBeforeSend(func(context.Context, *Send) {})
+
+	if api.At.AtMobiles[0] != "13800000000" || api.At.AtUserIDs[0] != "user" {
+		t.Fatalf("Clone() shares At slices: %+v", api.At)
+	}
+	if api.Msg.(ActionsCard).Btns[0].Title != "a" {
+		t.Fatal("Clone() shares message buttons")
+	}
+	if api.ExtraQuery["k"] != "v" || len(api.beforeSend) != 0 {
+		t.Fatal("Clone() shares query or hooks")
+	}
+
+	feed := FeedCard{Links: feedLinks(2)}
+	copied := CopyMsg(feed).(FeedCard)
+	copied.Links[0].Title = "changed"
+	if feed.Links[0].Title != "title0" {
+		t.Fatal("CopyMsg() shares feed card links")
+	}
+}
+
+// TestSendCloneRace 需要使用 go test -race 运行才能发现数据竞争
+func TestSendCloneRace(t *testing.T) {
+	template := &Send{Msg: Text{Content: "t"}, At: At{AtMobiles: make([]string, 1, 8)}}
+	appendMobile := func(s *Send, i int) {
+		s.At.AtMobiles = append(s.At.AtMobiles, fmt.Sprint(i))
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := template.Clone()
+			appendMobile(c, i)
+			if want := []string{"", fmt.Sprint(i)}; !reflect.DeepEqual(c.At.AtMobiles, want) {
+				t.Errorf("clone %d AtMobiles = %q, want %q", i, c.At.AtMobiles, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(template.At.AtMobiles) != 1 {
+		t.Fatalf("template modified: %q", template.At.AtMobiles)
+	}
+}
+
+func TestImmutableSend(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().AtMobiles("13800000000")
+	bot.ImmutableSend = true
+	bot.DefaultHandlers = []SendHandler{func(s *Send) error {
+		s.At.AtMobiles = append(s.At.AtMobiles, "13900000000")
+		return nil
+	}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := bot.SendText("immutable"); err != nil {
+				t.Errorf("SendText() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, r := range srv.Requests() {
+		if mobiles := r.JSON(t)["at"].(map[string]any)["atMobiles"].([]any); len(mobiles) != 2 {
+			t.Fatalf("atMobiles = %v, want default plus one appended", mobiles)
+		}
+	}
+	if got := bot.DefaultAt.AtMobiles; !reflect.DeepEqual(got, []string{"13800000000"}) {
+		t.Fatalf("DefaultAt modified: %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package dingtalk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// ErrMissingToken 配置中既没有凭证也没有凭证解析器
+var ErrMissingToken = errors.New("dingtalk: missing token")
+
+// BotConfig 机器人的配置，与运行时状态分离，便于从配置文件或环境变量加载
+type BotConfig struct {
+	// 名称，可自定义
+	Name string `json:"name" yaml:"name" toml:"name" env:"DINGTALK_NAME"`
+
+	// 调用接口的凭证，钉钉提供的 Webhook 链接中 access_token 的值
+	Token string `json:"token" yaml:"token" toml:"token" env:"DINGTALK_TOKEN"`
+
+	// 安全密钥，创建机器人时在安全设置项选择了加签后，钉钉提供的 SEC 开头的字符串
+	Secret string `json:"secret" yaml:"secret" toml:"secret" env:"DINGTALK_SECRET"`
+
+	// 自定义关键词，创建机器人时在安全设置项填入的所有关键词
+	Keywords []string `json:"keywords" yaml:"keywords" toml:"keywords" env:"DINGTALK_KEYWORDS"`
+
+	// 全局请求超时时间，值为正时生效
+	Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout" env:"DINGTALK_TIMEOUT"`
+
+	// 发送群消息的接口地址，为空时使用 DefaultWebhook
+	BaseURL string `json:"baseURL" yaml:"baseURL" toml:"baseURL" env:"DINGTALK_BASE_URL"`
+}
+
+// Validate 检测配置，除 Bot.Validate 的检测外，接口地址必须为 http 或 https 链接
+func (c BotConfig) Validate() error {
+	var errs []error
+	if err := c.bot().Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dingtalk: invalid base url: %w", err))
+		} else if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("dingtalk: invalid base url: %q", c.BaseURL))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bot 根据配置创建机器人，不检测配置
+func (c BotConfig) bot() *Bot {
+	return &Bot{
+		Name:     c.Name,
+		Token:    c.Token,
+		Secret:   c.Secret,
+		Keywords: slices.Clone(c.Keywords),
+		Timeout:  c.Timeout,
+		BaseURL:  c.BaseURL,
+	}
+}
+
+// NewBotFromConfig 检测配置并创建机器人
+func NewBotFromConfig(cfg BotConfig) (*Bot, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg.bot(), nil
+}
+
+// Config 返回机器人当前配置的快照，是 NewBotFromConfig 的逆操作
+func (b *Bot) Config() BotConfig {
+	if b == nil {
+		return BotConfig{}
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return BotConfig{
+		Name:     b.Name,
+		Token:    b.Token,
+		Secret:   b.Secret,
+		Keywords: slices.Clone(b.Keywords),
+		Timeout:  b.Timeout,
+		BaseURL:  b.BaseURL,
+	}
+}
@@ -0,0 +1,59 @@
+package dingtalk
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewBotFromConfig(t *testing.T) {
+	bot := &Bot{Name: "alerts", Token: testToken, Secret: "SECsecret", Keywords: []string{"ALERT"}, Timeout: time.Second}
+	cfg := bot.Config()
+	got, err := NewBotFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Config(), cfg) {
+		t.Fatalf("NewBotFromConfig(bot.Config()).Config() = %+v, want %+v", got.Config(), cfg)
+	}
+	cfg.Keywords[0] = "changed"
+	if got.Keywords[0] != "ALERT" || bot.Keywords[0] != "ALERT" {
+		t.Fatal("Config() and NewBotFromConfig() share the keywords slice")
+	}
+	if (*Bot)(nil).Config().Token != "" {
+		t.Fatal("Config() on nil bot returned a token")
+	}
+}
+
+func TestBotConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  BotConfig
+		want string
+	}{
+		{"missing token", BotConfig{}, "dingtalk: missing token"},
+		{"invalid token", BotConfig{Token: "abc"}, `dingtalk: invalid token format: "***"`},
+		{"invalid secret", BotConfig{Token: testToken, Secret: "secret"}, "dingtalk: secret must start with SEC"},
+		{"empty keyword", BotConfig{Token: testToken, Keywords: []string{""}}, "dingtalk: keyword 0 is empty"},
+		{"negative timeout", BotConfig{Token: testToken, Timeout: -time.Second}, "dingtalk: negative timeout: -1s"},
+		{"invalid base url", BotConfig{Token: "proxy-token", BaseURL: "ftp://example.com"}, `dingtalk: invalid base url: "ftp://example.com"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("Validate() = %v, want %s", err, tt.want)
+			}
+			if bot, err := NewBotFromConfig(tt.cfg); bot != nil || err == nil {
+				t.Fatalf("NewBotFromConfig() = %v, %v, want error", bot, err)
+			}
+		})
+	}
+	if err := (BotConfig{}).Validate(); !errors.Is(err, ErrMissingToken) {
+		t.Fatalf("Validate() = %v, want ErrMissingToken", err)
+	}
+	if err := (BotConfig{Token: TestWebhookToken}).Validate(); err != nil {
+		t.Fatalf("Validate() with TestWebhookToken = %v", err)
+	}
+}
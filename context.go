@@ -0,0 +1,30 @@
+package dingtalk
+
+import "context"
+
+type (
+	tokenKey  struct{}
+	secretKey struct{}
+)
+
+// WithToken 返回携带凭证的上下文，发送消息时优先使用该凭证，适用于每个请求使用不同凭证的场景
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// WithSecret 返回携带安全密钥的上下文，发送消息时优先使用该安全密钥
+func WithSecret(ctx context.Context, secret string) context.Context {
+	return context.WithValue(ctx, secretKey{}, secret)
+}
+
+// tokenFromContext 返回上下文中携带的凭证
+func tokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey{}).(string)
+	return token, ok
+}
+
+// secretFromContext 返回上下文中携带的安全密钥
+func secretFromContext(ctx context.Context) (string, bool) {
+	secret, ok := ctx.Value(secretKey{}).(string)
+	return secret, ok
+}
@@ -0,0 +1,57 @@
+package dingtalk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+)
+
+// checkSign 检测请求中的签名是否由 secret 生成
+func checkSign(t *testing.T, query url.Values, secret string) {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(query.Get("timestamp") + "\n" + secret))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); query.Get("sign") != want {
+		t.Fatalf("sign = %q, want signed with %q", query.Get("sign"), secret)
+	}
+}
+
+func TestWithToken(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Secret = "SECbot"
+	override := "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+
+	if err := bot.SendWithContext(context.Background(), Text{Content: "bot"}); err != nil {
+		t.Fatal(err)
+	}
+	query := srv.Last(t).Query
+	if got := query.Get("access_token"); got != testToken {
+		t.Fatalf("access_token = %q, want bot token", got)
+	}
+	checkSign(t, query, "SECbot")
+
+	ctx := WithSecret(WithToken(context.Background(), override), "SECtenant")
+	if err := bot.SendWithContext(ctx, Text{Content: "tenant"}); err != nil {
+		t.Fatal(err)
+	}
+	query = srv.Last(t).Query
+	if got := query.Get("access_token"); got != override {
+		t.Fatalf("access_token = %q, want context token %q", got, override)
+	}
+	checkSign(t, query, "SECtenant")
+
+	bot.TokenResolver = func(context.Context) (string, error) { return "resolved", nil }
+	if err := bot.SendWithContext(WithToken(context.Background(), override), Text{Content: "resolver"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).Query.Get("access_token"); got != override {
+		t.Fatalf("access_token = %q, want context token to take precedence over TokenResolver", got)
+	}
+	if bot.Token != testToken || bot.Secret != "SECbot" {
+		t.Fatal("context values modified the bot")
+	}
+}
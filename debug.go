@@ -0,0 +1,110 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+)
+
+// debugCapture 通过 Debug 注册的单次抓取
+type debugCapture struct {
+	ctx context.Context
+	buf *bytes.Buffer
+}
+
+// Debug 抓取该机器人在 ctx 结束前发出的下一次请求，包括请求地址、请求头、请求体和响应体，
+// 并以 HTTP 报文的格式写入返回的 io.Writer ，其底层类型为 *bytes.Buffer ，应在发送结束后读取。
+// 抓取内容包含完整的凭证和签名，提交问题前请自行脱敏，多次调用时只有最后一次生效
+func (b *Bot) Debug(ctx context.Context) io.Writer {
+	buf := &bytes.Buffer{}
+	if b == nil {
+		return buf
+	}
+	b.mu.Lock()
+	b.debugNext = &debugCapture{ctx: ctx, buf: buf}
+	b.mu.Unlock()
+	return buf
+}
+
+// DebugAll 将之后每次发送的请求和响应写入 w ，值为空时关闭
+func (b *Bot) DebugAll(w io.Writer) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if w == nil {
+		b.debugAll = nil
+	} else {
+		b.debugAll = &lockedWriter{w: w}
+	}
+	b.mu.Unlock()
+}
+
+// debugWriter 返回本次发送需要写入的调试输出，并消耗通过 Debug 注册的单次抓取，没有调试输出时返回空
+func (b *Bot) debugWriter() io.Writer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var writers []io.Writer
+	if b.debugAll != nil {
+		writers = append(writers, b.debugAll)
+	}
+	if next := b.debugNext; next != nil {
+		b.debugNext = nil
+		if next.ctx.Err() == nil {
+			writers = append(writers, next.buf)
+		}
+	}
+	switch len(writers) {
+	case 0:
+		return nil
+	case 1:
+		return writers[0]
+	default:
+		return io.MultiWriter(writers...)
+	}
+}
+
+// lockedWriter 保证每次写入不会与其他发送的输出交错
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// debugTransport 将请求和响应以 HTTP 报文的格式写入 w
+type debugTransport struct {
+	base http.RoundTripper
+	w    io.Writer
+}
+
+func (t debugTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---------- request ----------\n")
+	if dump, err := httputil.DumpRequestOut(r, true); err != nil {
+		buf.WriteString("failed to dump request: " + err.Error() + "\n")
+	} else {
+		buf.Write(dump)
+	}
+	resp, err := t.base.RoundTrip(r)
+	buf.WriteString("\n---------- response ----------\n")
+	if err != nil {
+		buf.WriteString("failed to send request: " + err.Error() + "\n")
+	} else if dump, err := httputil.DumpResponse(resp, true); err != nil {
+		buf.WriteString("failed to dump response: " + err.Error() + "\n")
+	} else {
+		buf.Write(dump)
+	}
+	buf.WriteString("\n")
+	t.w.Write(buf.Bytes())
+	return resp, err
+}
+
+var _ http.RoundTripper = debugTransport{}
@@ -0,0 +1,73 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBotDebug(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	w := bot.Debug(context.Background())
+	if err := bot.SendText("first", func(s *Send) error {
+		s.RequestID = "req-debug"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dump := w.(*bytes.Buffer).String()
+	for _, want := range []string{
+		"POST /?access_token=" + testToken,
+		"Content-Type: application/json",
+		"X-Request-Id: req-debug",
+		`"content":"first"`,
+		"HTTP/1.1 200 OK",
+		`{"errcode":0,"errmsg":"ok"}`,
+	} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("debug output missing %q:\n%s", want, dump)
+		}
+	}
+	if err := bot.SendText("second"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(w.(*bytes.Buffer).String(), "second") {
+		t.Fatal("Debug() captured more than the next send")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w = bot.Debug(ctx)
+	cancel()
+	if err := bot.SendText("cancelled"); err != nil {
+		t.Fatal(err)
+	}
+	if w.(*bytes.Buffer).Len() != 0 {
+		t.Fatal("Debug() captured a send after its context was cancelled")
+	}
+}
+
+func TestBotDebugAll(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	var buf bytes.Buffer
+	bot.DebugAll(&buf)
+	for _, content := range []string{"one", "two"} {
+		if err := bot.SendText(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(buf.String(), "---------- request ----------"); n != 2 {
+		t.Fatalf("DebugAll() wrote %d requests, want 2:\n%s", n, buf.String())
+	}
+	bot.DebugAll(nil)
+	if err := bot.SendText("three"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "three") {
+		t.Fatal("DebugAll(nil) did not stop capturing")
+	}
+}
@@ -0,0 +1,127 @@
+package dingtalk
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrDuplicate 去重窗口内已经发送过相同的消息
+var ErrDuplicate = errors.New("dingtalk: duplicate message suppressed")
+
+// dedupEntry 去重缓存中的一条记录
+type dedupEntry struct {
+	api  *Send
+	sent time.Time
+}
+
+// dedupCache 消息去重缓存，记录每个请求哈希值最近一次发送的请求和时间
+type dedupCache struct {
+	window time.Duration
+	mu     sync.Mutex
+	sent   map[[sha256.Size]byte]dedupEntry
+}
+
+// equal 对于实现了 Equaler 的消息，逐字段比较两次请求是否相同，发往不同接口地址或凭证的请求总是不同
+func equal(eq Equaler, a, b *Send) bool {
+	return a.AccessToken == b.AccessToken && a.RawURL() == b.RawURL() &&
+		eq.Equal(b.Msg) && a.MsgUUID == b.MsgUUID && reflect.DeepEqual(a.At, b.At)
+}
+
+// reserve 检测请求是否在窗口内发送过，未发送过则记录并返回真。
+// 消息实现了 Equaler 时优先逐字段比较，否则比较请求体哈希值
+func (d *dedupCache) reserve(api *Send, sum [sha256.Size]byte) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	for k, e := range d.sent {
+		if now.Sub(e.sent) >= d.window {
+			delete(d.sent, k)
+		}
+	}
+	if eq, ok := api.Msg.(Equaler); ok {
+		for _, e := range d.sent {
+			if equal(eq, api, e.api) {
+				return false
+			}
+		}
+	} else if _, ok := d.sent[sum]; ok {
+		return false
+	}
+	d.sent[sum] = dedupEntry{api: api, sent: now}
+	return true
+}
+
+// release 移除哈希值的记录，用于发送失败后允许重新发送
+func (d *dedupCache) release(sum [sha256.Size]byte) {
+	d.mu.Lock()
+	delete(d.sent, sum)
+	d.mu.Unlock()
+}
+
+// clear 清空缓存
+func (d *dedupCache) clear() {
+	d.mu.Lock()
+	d.sent = make(map[[sha256.Size]byte]dedupEntry)
+	d.mu.Unlock()
+}
+
+// sum 计算凭证、接口地址和请求体的哈希值
+func (s *Send) sum() ([sha256.Size]byte, error) {
+	data, err := s.body(context.Background())
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	h := sha256.New()
+	h.Write([]byte(s.AccessToken))
+	h.Write([]byte{0})
+	h.Write([]byte(s.RawURL()))
+	h.Write([]byte{0})
+	h.Write(data)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum, nil
+}
+
+// reserve 开启去重时检测请求是否重复，返回的函数用于在发送失败后移除记录
+func (b *Bot) reserve(api *Send) (release func(), err error) {
+	d := b.dedup
+	if d == nil {
+		return func() {}, nil
+	}
+	sum, err := api.sum()
+	if err != nil {
+		return nil, fmt.Errorf("dingtalk: failed to hash message: %w", err)
+	}
+	if !d.reserve(api, sum) {
+		return nil, ErrDuplicate
+	}
+	return func() { d.release(sum) }, nil
+}
+
+// SuppressDuplicates 开启消息去重，在窗口时间内向同一接口地址和凭证发送相同的消息（以最终请求体判断）会返回 ErrDuplicate ，窗口值不为正时关闭去重
+func (b *Bot) SuppressDuplicates(window time.Duration) *Bot {
+	if b == nil {
+		return nil
+	}
+	if window <= 0 {
+		b.dedup = nil
+		return b
+	}
+	b.dedup = &dedupCache{window: window, sent: make(map[[sha256.Size]byte]dedupEntry)}
+	return b
+}
+
+// ClearDedupCache 清空消息去重缓存
+func (b *Bot) ClearDedupCache() {
+	if b == nil {
+		return
+	}
+	if b.dedup != nil {
+		b.dedup.clear()
+	}
+}
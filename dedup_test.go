@@ -0,0 +1,105 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// plainMsg 未实现 Equaler 的消息，去重时比较请求哈希值
+type plainMsg struct {
+	Content string `json:"content"`
+}
+
+func (plainMsg) Type() MsgType {
+	return MsgText
+}
+
+func TestSuppressDuplicates(t *testing.T) {
+	for _, msg := range []Msg{Text{Content: "health ok"}, plainMsg{Content: "health ok"}} {
+		srv := newTestServer(t)
+		bot := srv.Bot().SuppressDuplicates(time.Hour)
+		ctx := context.Background()
+		if err := bot.SendWithContext(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+		if err := bot.SendWithContext(ctx, msg); !errors.Is(err, ErrDuplicate) {
+			t.Fatalf("%T: second send error = %v, want ErrDuplicate", msg, err)
+		}
+		if err := bot.SendWithContext(ctx, msg, AtAll); err != nil {
+			t.Fatalf("%T: send with different At error = %v", msg, err)
+		}
+		if err := bot.SendWithContext(WithToken(ctx, testToken[1:]+"0"), msg); err != nil {
+			t.Fatalf("%T: send with different token error = %v", msg, err)
+		}
+		if err := bot.SendWithContext(ctx, msg, Webhook(srv.URL+"/other")); err != nil {
+			t.Fatalf("%T: send to different webhook error = %v", msg, err)
+		}
+		bot.ClearDedupCache()
+		if err := bot.SendWithContext(ctx, msg); err != nil {
+			t.Fatalf("%T: send after ClearDedupCache error = %v", msg, err)
+		}
+		if n := len(srv.Requests()); n != 5 {
+			t.Fatalf("%T: server received %d requests, want 5", msg, n)
+		}
+	}
+}
+
+func TestSuppressDuplicatesExpiry(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().SuppressDuplicates(50 * time.Millisecond)
+	ctx := context.Background()
+	msg := Text{Content: "health ok"}
+	if err := bot.SendWithContext(ctx, msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.SendWithContext(ctx, msg); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("second send error = %v, want ErrDuplicate", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := bot.SendWithContext(ctx, msg); err != nil {
+		t.Fatalf("send after window error = %v", err)
+	}
+}
+
+func TestSuppressDuplicatesRelease(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrSendTooFast.Code, "send too fast"))
+	bot := srv.Bot().SuppressDuplicates(time.Hour)
+	ctx := context.Background()
+	msg := Text{Content: "health ok"}
+	if err := bot.SendWithContext(ctx, msg); errors.Is(err, ErrDuplicate) || err == nil {
+		t.Fatalf("first send error = %v, want send error", err)
+	}
+	srv.SetHandler(nil)
+	if err := bot.SendWithContext(ctx, msg); err != nil {
+		t.Fatalf("send after failure error = %v", err)
+	}
+}
+
+// sameMsg 实现了 Equaler 且总是相等的消息，用于检测去重时优先逐字段比较
+type sameMsg struct {
+	Content string `json:"content"`
+}
+
+func (sameMsg) Type() MsgType {
+	return MsgText
+}
+
+func (sameMsg) Equal(other Msg) bool {
+	_, ok := other.(sameMsg)
+	return ok
+}
+
+func TestSuppressDuplicatesEqualer(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().SuppressDuplicates(time.Hour)
+	ctx := context.Background()
+	if err := bot.SendWithContext(ctx, sameMsg{Content: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.SendWithContext(ctx, sameMsg{Content: "b"}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("send of Equal msg error = %v, want ErrDuplicate", err)
+	}
+}
@@ -0,0 +1,145 @@
+package dingtalk
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DSNScheme 机器人 DSN 的协议名
+const DSNScheme = "dingtalk"
+
+// ParseDSN 解析形如 dingtalk://[name[:secret]@]token[?keywords=a&keywords=b&timeout=10s&limit=20&baseURL=https%3A%2F%2Fproxy] 的 DSN
+func ParseDSN(dsn string) (*Bot, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("dingtalk: failed to parse dsn: %w", err)
+	}
+	if u.Scheme != DSNScheme {
+		return nil, fmt.Errorf("dingtalk: invalid dsn scheme: %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("dingtalk: missing token in dsn")
+	}
+	b := &Bot{Token: u.Host}
+	if u.User != nil {
+		b.Name = u.User.Username()
+		b.Secret, _ = u.User.Password()
+	}
+	query := u.Query()
+	b.Keywords = query["keywords"]
+	if s := query.Get("timeout"); s != "" {
+		b.Timeout, err = time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("dingtalk: invalid dsn timeout: %w", err)
+		}
+	}
+	if s := query.Get("limit"); s != "" {
+		b.Limit, err = strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("dingtalk: invalid dsn limit: %w", err)
+		}
+	}
+	b.BaseURL = query.Get("baseURL")
+	return b, nil
+}
+
+// DSN 将机器人配置序列化为 DSN ，是 ParseDSN 的逆操作
+func (b *Bot) DSN() string {
+	if b == nil {
+		return ""
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	u := &url.URL{Scheme: DSNScheme, Host: b.Token}
+	if b.Secret != "" {
+		u.User = url.UserPassword(b.Name, b.Secret)
+	} else if b.Name != "" {
+		u.User = url.User(b.Name)
+	}
+	query := url.Values{}
+	for _, keyword := range b.Keywords {
+		query.Add("keywords", keyword)
+	}
+	if b.Timeout != 0 {
+		query.Set("timeout", b.Timeout.String())
+	}
+	if b.Limit != 0 {
+		query.Set("limit", strconv.Itoa(b.Limit))
+	}
+	if b.BaseURL != "" {
+		query.Set("baseURL", b.BaseURL)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// setConfig 使用另一个机器人的配置字段覆盖当前机器人的配置字段，
+// keepLimit 为真时保留每分钟发送消息限制量，用于重新加载配置，因为该值在首次发送后无法修改
+func (b *Bot) setConfig(c *Bot, keepLimit bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Name = c.Name
+	b.Token = c.Token
+	b.Secret = c.Secret
+	b.Keywords = append([]string(nil), c.Keywords...)
+	b.Timeout = c.Timeout
+	if !keepLimit {
+		b.Limit = c.Limit
+	}
+	b.BaseURL = c.BaseURL
+}
+
+func (b *Bot) MarshalText() ([]byte, error) {
+	if b == nil {
+		return nil, ErrNilBot
+	}
+	return []byte(b.DSN()), nil
+}
+
+func (b *Bot) UnmarshalText(text []byte) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	c, err := ParseDSN(string(text))
+	if err != nil {
+		return err
+	}
+	b.setConfig(c, false)
+	return nil
+}
+
+var _ encoding.TextMarshaler = (*Bot)(nil)
+var _ encoding.TextUnmarshaler = (*Bot)(nil)
+
+// botJSON 用于按照 Bot 的结构体标签序列化，避免递归调用 MarshalJSON
+type botJSON Bot
+
+func (b *Bot) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal((*botJSON)(b))
+}
+
+// UnmarshalJSON 支持 JSON 对象，也支持 DSN 字符串
+func (b *Bot) UnmarshalJSON(data []byte) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte{'"'}) {
+		var dsn string
+		if err := json.Unmarshal(data, &dsn); err != nil {
+			return err
+		}
+		return b.UnmarshalText([]byte(dsn))
+	}
+	return json.Unmarshal(data, (*botJSON)(b))
+}
+
+var _ json.Marshaler = (*Bot)(nil)
+var _ json.Unmarshaler = (*Bot)(nil)
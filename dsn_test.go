@@ -0,0 +1,118 @@
+package dingtalk
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDSNRoundTrip(t *testing.T) {
+	bot := &Bot{
+		Name:     "alert",
+		Token:    testToken,
+		Secret:   "SECsecret",
+		Keywords: []string{"告警", "通知"},
+		Timeout:  10 * time.Second,
+		Limit:    20,
+		BaseURL:  "https://proxy.example.com/robot/send?x=1",
+	}
+	text, err := bot.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Bot
+	if err = got.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != bot.Name || got.Token != bot.Token || got.Secret != bot.Secret ||
+		!reflect.DeepEqual(got.Keywords, bot.Keywords) || got.Timeout != bot.Timeout ||
+		got.Limit != bot.Limit || got.BaseURL != bot.BaseURL {
+		t.Fatalf("UnmarshalText(%q) = %v, want %v", text, got.Config(), bot.Config())
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	bot, err := ParseDSN("dingtalk://ops@" + testToken + "?keywords=a&keywords=b&timeout=3s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Name != "ops" || bot.Token != testToken || bot.Secret != "" || bot.Timeout != 3*time.Second {
+		t.Fatalf("ParseDSN() = %v", bot.Config())
+	}
+	if !reflect.DeepEqual(bot.Keywords, []string{"a", "b"}) {
+		t.Fatalf("Keywords = %q, want [a b]", bot.Keywords)
+	}
+	for _, dsn := range []string{
+		"http://" + testToken,
+		"dingtalk://",
+		"dingtalk://" + testToken + "?timeout=x",
+		"dingtalk://" + testToken + "?limit=x",
+	} {
+		if _, err := ParseDSN(dsn); err == nil {
+			t.Errorf("ParseDSN(%q) error = nil, want error", dsn)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	bot := &Bot{
+		Name:          "alert",
+		Token:         testToken,
+		Secret:        "SECsecret",
+		Keywords:      []string{"告警"},
+		Timeout:       time.Second,
+		Limit:         10,
+		BaseURL:       "https://proxy.example.com",
+		MaxContentLen: 1024,
+		HistoryCap:    8,
+		DefaultAt:     At{AtMobiles: []string{"13800000000"}},
+	}
+	data, err := json.Marshal(bot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Bot
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != bot.Name || got.Token != bot.Token || got.Secret != bot.Secret ||
+		!reflect.DeepEqual(got.Keywords, bot.Keywords) || got.Timeout != bot.Timeout ||
+		got.Limit != bot.Limit || got.BaseURL != bot.BaseURL || got.MaxContentLen != bot.MaxContentLen ||
+		got.HistoryCap != bot.HistoryCap || !got.DefaultAt.Equals(bot.DefaultAt) {
+		t.Fatalf("json round trip of %s lost fields", data)
+	}
+}
+
+func TestUnmarshalJSONDSN(t *testing.T) {
+	var cfg struct {
+		Bot Bot `json:"bot"`
+	}
+	data := []byte(`{"bot":"dingtalk://ops:SECsecret@` + testToken + `?keywords=k"}`)
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Bot.Name != "ops" || cfg.Bot.Secret != "SECsecret" || cfg.Bot.Token != testToken {
+		t.Fatalf("UnmarshalJSON(%s) = %v", data, cfg.Bot.Config())
+	}
+}
+
+func TestUnmarshalTextConcurrent(t *testing.T) {
+	bot := &Bot{Token: testToken}
+	text := []byte("dingtalk://" + testToken + "?keywords=a")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			bot.UnmarshalText(text)
+		}()
+		go func() {
+			defer wg.Done()
+			bot.DSN()
+			bot.keywords()
+		}()
+	}
+	wg.Wait()
+}
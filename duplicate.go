@@ -0,0 +1,67 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Drelf2018/req"
+)
+
+// Duplicate 将消息并发发送至 tokens 中除当前凭证外的其他机器人，原消息总是会发送，并与副本同时进行，
+// 等待全部完成后返回原消息的响应和第一个错误，原消息的错误优先。
+// 副本使用与原消息相同的会话、上下文、接口地址和签名，因此只支持未开启加签或安全密钥与当前机器人相同的机器人
+func Duplicate(tokens []string) SendHandler {
+	return func(s *Send) error {
+		s.duplicates = append(s.duplicates, tokens...)
+		return nil
+	}
+}
+
+// postDuplicates 并发发送请求的副本，返回等待全部副本完成并返回第一个错误的函数，
+// 副本在返回前创建完毕，除凭证外与原请求相同，但不执行原请求的钩子函数
+func postDuplicates(ctx context.Context, session *req.Session, api *Send) (wait func() error) {
+	var (
+		wg     sync.WaitGroup
+		tokens = api.duplicates
+		errs   = make([]error, len(tokens))
+	)
+	for i, t := range tokens {
+		if t == "" || t == api.AccessToken || slices.Index(tokens, t) != i {
+			continue
+		}
+		c := api.Clone()
+		c.AccessToken = t
+		c.duplicates, c.beforeSend, c.afterSend = nil, nil, nil
+		wg.Add(1)
+		go func(i int, c *Send) {
+			defer wg.Done()
+			if _, err := postSendWithRetry(ctx, session, c); err != nil {
+				errs[i] = fmt.Errorf("dingtalk: failed to duplicate to token %d: %w", i, err)
+			}
+		}(i, c)
+	}
+	return func() error {
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// postSendWithDuplicates 同时发送原请求和请求的副本，返回原请求的响应和第一个错误
+func postSendWithDuplicates(ctx context.Context, session *req.Session, api *Send) (SendResponse, error) {
+	if len(api.duplicates) == 0 {
+		return postSendWithRetry(ctx, session, api)
+	}
+	wait := postDuplicates(ctx, session, api)
+	r, err := postSendWithRetry(ctx, session, api)
+	if dupErr := wait(); err == nil {
+		err = dupErr
+	}
+	return r, err
+}
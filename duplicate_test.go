@@ -0,0 +1,121 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// otherToken 副本使用的凭证
+const otherToken = "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+
+// routeTransport 将凭证为 token 的请求转发至 target
+type routeTransport struct {
+	token  string
+	target *url.URL
+}
+
+func (t routeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Query().Get("access_token") == t.token {
+		r = r.Clone(r.Context())
+		r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// duplicateServers 创建两个测试服务器，机器人发送至 primary ，凭证为 otherToken 的请求由客户端转发至 mirror
+func duplicateServers(t *testing.T) (bot *Bot, primary, mirror *testServer) {
+	primary, mirror = newTestServer(t), newTestServer(t)
+	target, _ := url.Parse(mirror.URL)
+	bot = primary.Bot()
+	bot.Secret = "SECduplicate"
+	bot.SetHTTPClient(&http.Client{Transport: routeTransport{token: otherToken, target: target}})
+	return
+}
+
+func TestDuplicate(t *testing.T) {
+	bot, primary, mirror := duplicateServers(t)
+
+	err := bot.SendText("alert", AtMobile("13800000000"), Duplicate([]string{testToken, otherToken, otherToken, ""}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(primary.Requests()); n != 1 {
+		t.Fatalf("primary received %d requests, want 1", n)
+	}
+	if n := len(mirror.Requests()); n != 1 {
+		t.Fatalf("mirror received %d requests, want 1", n)
+	}
+	for name, r := range map[string]testRequest{"primary": primary.Last(t), "mirror": mirror.Last(t)} {
+		checkSign(t, r.Query, bot.Secret)
+		body := r.JSON(t)
+		if got := body["text"].(map[string]any)["content"]; got != "alert" {
+			t.Fatalf("%s content = %v, want alert", name, got)
+		}
+		if got := body["at"].(map[string]any)["atMobiles"].([]any); len(got) != 1 || got[0] != "13800000000" {
+			t.Fatalf("%s atMobiles = %v", name, got)
+		}
+	}
+	if got := mirror.Last(t).Query.Get("access_token"); got != otherToken {
+		t.Fatalf("mirror access_token = %q, want %q", got, otherToken)
+	}
+}
+
+func TestDuplicateError(t *testing.T) {
+	bot, primary, mirror := duplicateServers(t)
+	mirror.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+
+	err := bot.SendText("alert", Duplicate([]string{otherToken}))
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("SendText() = %v, want ErrInvalidToken", err)
+	}
+	// 副本发送失败时原消息仍然会发送
+	if n := len(primary.Requests()); n != 1 {
+		t.Fatalf("primary received %d requests after the mirror failed, want 1", n)
+	}
+	if got := primary.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != "alert" {
+		t.Fatalf("primary content = %v, want alert", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bot.SendWithContext(ctx, Text{Content: "cancelled"}, Duplicate([]string{otherToken})); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendWithContext() with cancelled context = %v, want context.Canceled", err)
+	}
+	if n := len(mirror.Requests()); n != 1 {
+		t.Fatalf("mirror received %d requests, want 1", n)
+	}
+	if n := len(primary.Requests()); n != 1 {
+		t.Fatalf("primary received %d requests, want 1", n)
+	}
+}
+
+func TestDuplicateResponse(t *testing.T) {
+	bot, primary, mirror := duplicateServers(t)
+	mirror.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	primary.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(TraceIDHeader, "primary-trace")
+		replyError(0, "ok")(w, r)
+	})
+
+	ctx := context.Background()
+	api, err := bot.NewSend(ctx, Text{Content: "alert"}, Duplicate([]string{otherToken}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := postSendWithDuplicates(ctx, bot.session(api), api)
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("postSendWithDuplicates() = %v, want the mirror's ErrInvalidToken", err)
+	}
+	if r.TraceID != "primary-trace" || r.ErrMsg != "ok" {
+		t.Fatalf("postSendWithDuplicates() = %+v, want the primary response", r)
+	}
+
+	// 原消息的错误优先于副本的错误
+	primary.SetHandler(replyError(ErrSignatureExpired.Code, "sign not match"))
+	if err := bot.SendText("alert", Duplicate([]string{otherToken})); !errors.Is(err, ErrSignatureExpired) {
+		t.Fatalf("SendText() = %v, want the primary's ErrSignatureExpired", err)
+	}
+}
@@ -0,0 +1,38 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+)
+
+// SendWithFallback 发送消息，钉钉接口返回错误码时改为发送备用消息，网络错误等其他错误会直接返回。
+// 备用消息也发送失败时返回两者合并的错误
+func (b *Bot) SendWithFallback(ctx context.Context, primary Msg, fallback Msg, handlers ...SendHandler) error {
+	err := b.SendWithContext(ctx, primary, handlers...)
+	var sendErr SendError
+	if !errors.As(err, &sendErr) || sendErr.ErrCode == 0 {
+		return err
+	}
+	if fallbackErr := b.SendWithContext(ctx, fallback, handlers...); fallbackErr != nil {
+		return errors.Join(err, fallbackErr)
+	}
+	return nil
+}
+
+// SendMarkdownWithTextFallback 发送 markdown 类型消息，钉钉接口返回错误码时改为发送由标题和正文组成的文本类型消息
+func (b *Bot) SendMarkdownWithTextFallback(ctx context.Context, title, text string, handlers ...SendHandler) error {
+	return b.SendWithFallback(ctx, Markdown{Title: title, Text: text}, Text{Content: title + "\n" + text}, handlers...)
+}
+
+// SendOrElse 发送消息，发送失败时使用该错误调用 fallback ，例如写入数据库或发送邮件。
+// fallback 也失败时返回两者合并的错误，fallback 成功时返回空
+func (b *Bot) SendOrElse(ctx context.Context, primary Msg, fallback func(err error) error, handlers ...SendHandler) error {
+	err := b.SendWithContext(ctx, primary, handlers...)
+	if err == nil || fallback == nil {
+		return err
+	}
+	if fallbackErr := fallback(err); fallbackErr != nil {
+		return errors.Join(err, fallbackErr)
+	}
+	return nil
+}
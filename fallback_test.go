@@ -0,0 +1,98 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestSendWithFallback(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		if len(srv.Requests()) == 1 {
+			replyError(ErrSendTooFast.Code, "send too fast")(w, r)
+			return
+		}
+		replyError(0, "ok")(w, r)
+	})
+	bot := srv.Bot()
+	err := bot.SendMarkdownWithTextFallback(context.Background(), "disk", "### full")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("server received %d requests, want 2", len(requests))
+	}
+	if requests[0].JSON(t)["msgtype"] != "markdown" {
+		t.Fatalf("primary payload = %s", requests[0].Body)
+	}
+	body := requests[1].JSON(t)
+	if body["msgtype"] != "text" || body["text"].(map[string]any)["content"] != "disk\n### full" {
+		t.Fatalf("fallback payload = %s", requests[1].Body)
+	}
+}
+
+func TestSendWithFallbackBothFail(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	err := srv.Bot().SendWithFallback(context.Background(), Markdown{Title: "a", Text: "b"}, Text{Content: "c"})
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("SendWithFallback() error = %v, want ErrInvalidToken", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Fatalf("server received %d requests, want 2", n)
+	}
+}
+
+func TestSendWithFallbackNetworkError(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	srv.Close()
+	var fallbackSent bool
+	err := bot.SendWithFallback(context.Background(), Markdown{Title: "a", Text: "b"}, Text{Content: "c"}, func(s *Send) error {
+		if _, ok := s.Msg.(Text); ok {
+			fallbackSent = true
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("SendWithFallback() error = nil, want network error")
+	}
+	var se SendError
+	if errors.As(err, &se) {
+		t.Fatalf("SendWithFallback() error = %v, want network error", err)
+	}
+	if fallbackSent {
+		t.Fatal("fallback sent on network error")
+	}
+}
+
+func TestSendOrElse(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	var called []error
+	fallback := func(err error) error {
+		called = append(called, err)
+		return nil
+	}
+
+	if err := bot.SendOrElse(context.Background(), Text{Content: "ok"}, fallback); err != nil || len(called) != 0 {
+		t.Fatalf("SendOrElse() = %v, fallback called %d times, want success without fallback", err, len(called))
+	}
+
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	if err := bot.SendOrElse(context.Background(), Text{Content: "fail"}, fallback); err != nil {
+		t.Fatalf("SendOrElse() with successful fallback = %v, want nil", err)
+	}
+	if len(called) != 1 || !errors.Is(called[0], ErrInvalidToken) {
+		t.Fatalf("fallback got %v, want the send error", called)
+	}
+
+	errDB := errors.New("db down")
+	err := bot.SendOrElse(context.Background(), Text{Content: "fail"}, func(error) error { return errDB })
+	if !errors.Is(err, ErrInvalidToken) || !errors.Is(err, errDB) {
+		t.Fatalf("SendOrElse() = %v, want both errors joined", err)
+	}
+}
@@ -0,0 +1,32 @@
+package dingtalk
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNilMsg 消息为空
+var ErrNilMsg = errors.New("dingtalk: msg is nil")
+
+// FingerprintMsg 计算消息内容的指纹，相同的消息在不同进程中总是得到相同的指纹。
+//
+// 指纹为消息类型和消息内容 JSON 序列化结果的 MD5 值，这里只需要快速且稳定的散列，不用于安全场景
+func FingerprintMsg(msg Msg) ([16]byte, error) {
+	if msg == nil {
+		return [16]byte{}, ErrNilMsg
+	}
+	data, err := json.Marshal(map[string]any{"msgtype": msg.Type(), string(msg.Type()): msg})
+	if err != nil {
+		return [16]byte{}, fmt.Errorf("dingtalk: failed to fingerprint msg: %w", err)
+	}
+	return md5.Sum(data), nil
+}
+
+// FingerprintAt 计算被@的群成员信息的指纹，算法与 FingerprintMsg 相同
+func FingerprintAt(at At) [16]byte {
+	// At 只包含布尔值和字符串切片，序列化不会出错
+	data, _ := json.Marshal(at)
+	return md5.Sum(data)
+}
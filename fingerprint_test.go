@@ -0,0 +1,53 @@
+package dingtalk
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestFingerprintMsg(t *testing.T) {
+	a, err := FingerprintMsg(Text{Content: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 指纹在不同进程中保持不变，即 {"msgtype":"text","text":{"content":"hello"}} 的 MD5 值
+	if got := hex.EncodeToString(a[:]); got != "43b20c4aef20929597c45bdad2850d0f" {
+		t.Fatalf("FingerprintMsg() = %s, want stable value", got)
+	}
+	b, _ := FingerprintMsg(Text{Content: "hello"})
+	if a != b {
+		t.Fatal("FingerprintMsg() is not deterministic")
+	}
+	seen := map[[16]byte]Msg{}
+	for _, msg := range []Msg{
+		Text{Content: "hello"},
+		Text{Content: "hello "},
+		Text{Content: "Hello"},
+		Markdown{Title: "hello", Text: "hello"},
+		Markdown{Title: "hello", Text: "hello "},
+		Link{Title: "hello", Text: "hello"},
+		ActionCard{Title: "hello", Text: "hello"},
+	} {
+		fp, err := FingerprintMsg(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev, ok := seen[fp]; ok {
+			t.Fatalf("FingerprintMsg(%#v) collides with %#v", msg, prev)
+		}
+		seen[fp] = msg
+	}
+	if _, err = FingerprintMsg(nil); err != ErrNilMsg {
+		t.Fatalf("FingerprintMsg(nil) error = %v, want ErrNilMsg", err)
+	}
+}
+
+func TestFingerprintAt(t *testing.T) {
+	a := FingerprintAt(At{AtMobiles: []string{"13800000000"}})
+	if a != FingerprintAt(At{AtMobiles: []string{"13800000000"}}) {
+		t.Fatal("FingerprintAt() is not deterministic")
+	}
+	if a == FingerprintAt(At{AtUserIDs: []string{"13800000000"}}) || a == FingerprintAt(At{}) || FingerprintAt(At{}) == FingerprintAt(At{IsAtAll: true}) {
+		t.Fatal("FingerprintAt() collides for different At")
+	}
+}
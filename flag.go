@@ -0,0 +1,48 @@
+package dingtalk
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// BindFlags 根据字段的 long 标签将机器人的配置字段绑定到命令行参数上，应在首次发送消息前调用，详见 BindFlagsWithPrefix
+func (b *Bot) BindFlags(fs *pflag.FlagSet) error {
+	return b.BindFlagsWithPrefix(fs, "")
+}
+
+// BindFlagsWithPrefix 根据字段的 long 标签将机器人的配置字段绑定到命令行参数上，前缀不为空时参数名为 prefix-long 。
+// 解析命令行参数时会直接写入字段而不加锁，因此绑定和解析都应在首次发送消息前完成
+func (b *Bot) BindFlagsWithPrefix(fs *pflag.FlagSet, prefix string) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	value := reflect.ValueOf(b).Elem()
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		name := field.Tag.Get("long")
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "-" + name
+		}
+		ptr := value.FieldByIndex(field.Index).Addr().Interface()
+		switch p := ptr.(type) {
+		case *string:
+			fs.StringVar(p, name, *p, "")
+		case *[]string:
+			fs.StringSliceVar(p, name, *p, "")
+		case *time.Duration:
+			fs.DurationVar(p, name, *p, "")
+		case *int:
+			fs.IntVar(p, name, *p, "")
+		default:
+			return fmt.Errorf("dingtalk: unsupported flag type %s of field %s", field.Type, field.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package dingtalk
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestBindFlags(t *testing.T) {
+	bot := &Bot{Name: "default", Limit: 5}
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := bot.BindFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+	if f := fs.Lookup("name"); f == nil || f.DefValue != "default" {
+		t.Fatalf("flag name not bound with default value: %v", f)
+	}
+	err := fs.Parse([]string{
+		"--token", testToken,
+		"--secret", "SECsecret",
+		"--keywords", "a,b",
+		"--timeout", "5s",
+		"--baseURL", "https://proxy.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Name != "default" || bot.Token != testToken || bot.Secret != "SECsecret" ||
+		bot.Timeout != 5*time.Second || bot.Limit != 5 || bot.BaseURL != "https://proxy.example.com" {
+		t.Fatalf("fields not populated: %v", bot.Config())
+	}
+	if !reflect.DeepEqual(bot.Keywords, []string{"a", "b"}) {
+		t.Fatalf("Keywords = %q, want [a b]", bot.Keywords)
+	}
+}
+
+func TestBindFlagsWithPrefix(t *testing.T) {
+	bot := &Bot{}
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := bot.BindFlagsWithPrefix(fs, "dingtalk"); err != nil {
+		t.Fatal(err)
+	}
+	if fs.Lookup("token") != nil {
+		t.Fatal("flag token should only be bound with prefix")
+	}
+	if err := fs.Parse([]string{"--dingtalk-token", testToken, "--dingtalk-limit=20"}); err != nil {
+		t.Fatal(err)
+	}
+	if bot.Token != testToken || bot.Limit != 20 {
+		t.Fatalf("fields not populated: token=%q limit=%d", bot.Token, bot.Limit)
+	}
+	if err := (*Bot)(nil).BindFlags(fs); err != ErrNilBot {
+		t.Fatalf("BindFlags() error = %v, want ErrNilBot", err)
+	}
+}
@@ -1,5 +1,26 @@
 module github.com/Drelf2018/dingtalk
 
-go 1.18
+go 1.21
 
-require github.com/Drelf2018/req v0.0.0-20260202023602-73315c9061f0
+require (
+	github.com/Drelf2018/req v0.0.0-20260202023602-73315c9061f0
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/prometheus/client_golang v1.20.5
+	github.com/spf13/pflag v1.0.10
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+	golang.org/x/sync v0.7.0
+	golang.org/x/time v0.5.0
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
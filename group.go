@@ -0,0 +1,183 @@
+package dingtalk
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BotGroup 机器人组
+type BotGroup struct {
+	Bots []*Bot
+
+	// 随机选择机器人时使用的权重，通过 WithWeights 设置
+	weights []int
+
+	mu sync.RWMutex
+}
+
+// NewBotGroup 新建机器人组
+func NewBotGroup(bots ...*Bot) *BotGroup {
+	return &BotGroup{Bots: bots}
+}
+
+// validateWeights 检测权重数量是否与机器人数量一致，且权重非负、总和为正
+func validateWeights(bots []*Bot, weights []int) error {
+	if len(weights) != len(bots) {
+		return fmt.Errorf("dingtalk: weights length %d does not match bots length %d", len(weights), len(bots))
+	}
+	var total int
+	for i, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("dingtalk: negative weight %d at index %d", w, i)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("dingtalk: sum of weights must be positive")
+	}
+	return nil
+}
+
+// Validate 检测机器人组的配置，设置了权重时权重数量必须与机器人数量一致
+func (g *BotGroup) Validate() error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.weights == nil {
+		return nil
+	}
+	return validateWeights(g.Bots, g.weights)
+}
+
+// WithWeights 设置随机选择机器人时使用的权重
+func (g *BotGroup) WithWeights(weights []int) *BotGroup {
+	g.mu.Lock()
+	g.weights = weights
+	g.mu.Unlock()
+	return g
+}
+
+// weighted 根据权重随机选择机器人，权重无效时返回空
+func weighted(bots []*Bot, weights []int) *Bot {
+	if validateWeights(bots, weights) != nil {
+		return nil
+	}
+	var total int
+	for _, w := range weights {
+		total += w
+	}
+	n := rand.Intn(total)
+	for i, w := range weights {
+		if n < w {
+			return bots[i]
+		}
+		n -= w
+	}
+	return nil
+}
+
+// Weighted 根据权重随机选择一个机器人，权重数量与机器人数量不一致时返回空
+func (g *BotGroup) Weighted(weights []int) *Bot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return weighted(g.Bots, weights)
+}
+
+// Any 随机选择一个机器人，设置了权重时按权重选择，机器人组为空时返回空
+func (g *BotGroup) Any() *Bot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.weights != nil {
+		return weighted(g.Bots, g.weights)
+	}
+	if len(g.Bots) == 0 {
+		return nil
+	}
+	return g.Bots[rand.Intn(len(g.Bots))]
+}
+
+// ByName 返回第一个名称与 name 相同（不区分大小写）的机器人
+func (g *BotGroup) ByName(name string) (*Bot, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, bot := range g.Bots {
+		if bot != nil && strings.EqualFold(bot.Name, name) {
+			return bot, true
+		}
+	}
+	return nil, false
+}
+
+// Names 返回所有机器人的名称
+func (g *BotGroup) Names() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	names := make([]string, 0, len(g.Bots))
+	for _, bot := range g.Bots {
+		if bot != nil {
+			names = append(names, bot.Name)
+		}
+	}
+	return names
+}
+
+// Add 添加机器人，设置了权重时新机器人的权重为 1
+func (g *BotGroup) Add(bot *Bot) *BotGroup {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.Bots = append(append(make([]*Bot, 0, len(g.Bots)+1), g.Bots...), bot)
+	if g.weights != nil {
+		g.weights = append(append(make([]int, 0, len(g.weights)+1), g.weights...), 1)
+	}
+	return g
+}
+
+// Remove 移除第一个名称与 name 相同（不区分大小写）的机器人及其权重，返回是否找到
+func (g *BotGroup) Remove(name string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, bot := range g.Bots {
+		if bot == nil || !strings.EqualFold(bot.Name, name) {
+			continue
+		}
+		g.Bots = append(append(make([]*Bot, 0, len(g.Bots)-1), g.Bots[:i]...), g.Bots[i+1:]...)
+		if i < len(g.weights) {
+			g.weights = append(append(make([]int, 0, len(g.weights)-1), g.weights[:i]...), g.weights[i+1:]...)
+		}
+		return true
+	}
+	return false
+}
+
+// Filter 返回仅包含满足条件的机器人的新机器人组，机器人不会被拷贝，设置了权重时保留对应的权重
+func (g *BotGroup) Filter(predicate func(*Bot) bool) *BotGroup {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	c := &BotGroup{}
+	for i, bot := range g.Bots {
+		if bot == nil || !predicate(bot) {
+			continue
+		}
+		c.Bots = append(c.Bots, bot)
+		if i < len(g.weights) {
+			c.weights = append(c.weights, g.weights[i])
+		}
+	}
+	return c
+}
+
+// Include 返回仅包含名称为 names 之一（不区分大小写）的机器人的新机器人组，详见 Filter
+func (g *BotGroup) Include(names ...string) *BotGroup {
+	return g.Filter(func(bot *Bot) bool {
+		return slices.ContainsFunc(names, bot.Is)
+	})
+}
+
+// Exclude 返回不包含名称为 names 之一（不区分大小写）的机器人的新机器人组，详见 Filter
+func (g *BotGroup) Exclude(names ...string) *BotGroup {
+	return g.Filter(func(bot *Bot) bool {
+		return !slices.ContainsFunc(names, bot.Is)
+	})
+}
@@ -0,0 +1,146 @@
+package dingtalk
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBotGroupWeighted(t *testing.T) {
+	bots := []*Bot{{Name: "fast"}, {Name: "legacy"}}
+	g := NewBotGroup(bots...).WithWeights([]int{20, 5})
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	const n = 10000
+	counts := make(map[*Bot]int)
+	for i := 0; i < n; i++ {
+		counts[g.Any()]++
+	}
+	for i, want := range []float64{0.8, 0.2} {
+		got := float64(counts[bots[i]]) / n
+		if math.Abs(got-want) > 0.05 {
+			t.Errorf("bot %s selected %.3f of the time, want %.2f±0.05", bots[i].Name, got, want)
+		}
+	}
+	if counts[nil] != 0 {
+		t.Fatalf("Any() returned nil %d times", counts[nil])
+	}
+	counts = make(map[*Bot]int)
+	for i := 0; i < n; i++ {
+		counts[g.Weighted([]int{0, 1})]++
+	}
+	if counts[bots[1]] != n {
+		t.Fatalf("Weighted([0 1]) selected legacy %d of %d times", counts[bots[1]], n)
+	}
+}
+
+func TestBotGroupValidate(t *testing.T) {
+	g := NewBotGroup(&Bot{}, &Bot{})
+	if err := g.Validate(); err != nil {
+		t.Fatalf("Validate() without weights error = %v", err)
+	}
+	for _, weights := range [][]int{{1}, {1, 2, 3}, {-1, 2}, {0, 0}} {
+		if err := g.WithWeights(weights).Validate(); err == nil {
+			t.Errorf("Validate() with weights %v error = nil", weights)
+		}
+		if bot := g.Any(); bot != nil {
+			t.Errorf("Any() with weights %v = %v, want nil", weights, bot)
+		}
+		if bot := g.Weighted(weights); bot != nil {
+			t.Errorf("Weighted(%v) = %v, want nil", weights, bot)
+		}
+	}
+}
+
+func TestBotGroupByName(t *testing.T) {
+	alpha, beta := &Bot{Name: "Alpha"}, &Bot{Name: "beta"}
+	g := NewBotGroup(alpha, nil)
+	if bot, ok := g.ByName("ALPHA"); !ok || bot != alpha {
+		t.Fatalf("ByName(ALPHA) = %p, %v, want alpha", bot, ok)
+	}
+	if _, ok := g.ByName("beta"); ok {
+		t.Fatal("ByName(beta) found bot before Add")
+	}
+
+	g.Add(beta)
+	if bot, ok := g.ByName("Beta"); !ok || bot != beta {
+		t.Fatalf("ByName(Beta) after Add = %p, %v, want beta", bot, ok)
+	}
+	if names := g.Names(); !reflect.DeepEqual(names, []string{"Alpha", "beta"}) {
+		t.Fatalf("Names() = %q", names)
+	}
+
+	if !g.Remove("alpha") {
+		t.Fatal("Remove(alpha) = false")
+	}
+	if g.Remove("alpha") {
+		t.Fatal("second Remove(alpha) = true")
+	}
+	if _, ok := g.ByName("alpha"); ok {
+		t.Fatal("ByName(alpha) found bot after Remove")
+	}
+	if names := g.Names(); !reflect.DeepEqual(names, []string{"beta"}) {
+		t.Fatalf("Names() after Remove = %q", names)
+	}
+}
+
+func TestBotGroupConcurrentAccess(t *testing.T) {
+	g := NewBotGroup()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("bot%d", i)
+			g.Add(&Bot{Name: name})
+			g.ByName(name)
+			g.Names()
+			if i%2 == 1 {
+				g.Remove(name)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := len(g.Names()); n != 8 {
+		t.Fatalf("got %d bots, want 8", n)
+	}
+}
+
+func TestBotGroupFilter(t *testing.T) {
+	euWest, euNorth, us := &Bot{Name: "EU-West"}, &Bot{Name: "eu-north"}, &Bot{Name: "US"}
+	g := NewBotGroup(euWest, nil, euNorth, us).WithWeights([]int{3, 1, 2, 5})
+
+	eu := g.Filter(func(b *Bot) bool { return strings.Contains(strings.ToUpper(b.Name), "EU") })
+	if !reflect.DeepEqual(eu.Bots, []*Bot{euWest, euNorth}) || eu == g {
+		t.Fatalf("Filter() = %v, want the EU bots in a new group", eu.Bots)
+	}
+	if eu.Bots[0] != euWest {
+		t.Fatal("Filter() copied the bots instead of sharing pointers")
+	}
+	if err := eu.Validate(); err != nil || !reflect.DeepEqual(eu.weights, []int{3, 2}) {
+		t.Fatalf("Filter() weights = %v, %v, want the matching weights kept", eu.weights, err)
+	}
+	if none := g.Filter(func(*Bot) bool { return false }); len(none.Bots) != 0 || none.Any() != nil {
+		t.Fatalf("Filter() matching nothing = %v, want an empty group", none.Bots)
+	}
+	if len(g.Bots) != 4 {
+		t.Fatal("Filter() modified the original group")
+	}
+
+	if got := g.Include("us", "EU-NORTH", "missing"); !reflect.DeepEqual(got.Bots, []*Bot{euNorth, us}) {
+		t.Fatalf("Include() = %v, want eu-north and US", got.Bots)
+	}
+	if got := g.Exclude("eu-west", "us"); !reflect.DeepEqual(got.Bots, []*Bot{euNorth}) {
+		t.Fatalf("Exclude() = %v, want eu-north", got.Bots)
+	}
+	if got := g.Include(); len(got.Bots) != 0 {
+		t.Fatalf("Include() without names = %v, want empty", got.Bots)
+	}
+	if got := g.Exclude(); len(got.Bots) != 3 {
+		t.Fatalf("Exclude() without names = %v, want all non-nil bots", got.Bots)
+	}
+}
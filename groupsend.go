@@ -0,0 +1,135 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// BotGroupError 机器人组并发发送时的错误，包含每个发送失败的机器人的错误
+type BotGroupError struct {
+	// 发送失败的错误，按机器人在组中的顺序排列，每个错误都带有机器人的名称
+	Errs []error
+
+	// 参与发送的机器人数量
+	total int
+}
+
+func (e *BotGroupError) Error() string {
+	errs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		errs[i] = err.Error()
+	}
+	return fmt.Sprintf("dingtalk: %d of %d sends failed: %s", len(e.Errs), e.total, strings.Join(errs, "; "))
+}
+
+// Unwrap 返回所有错误，使 errors.Is 和 errors.As 可以匹配其中的错误
+func (e *BotGroupError) Unwrap() []error {
+	return e.Errs
+}
+
+// SendWithContext 使用机器人组中的所有机器人并发发送消息，处理器会应用于每个机器人的发送，
+// 空的机器人会被跳过，存在发送失败时返回 *BotGroupError
+func (g *BotGroup) SendWithContext(ctx context.Context, msg Msg, handlers ...SendHandler) error {
+	g.mu.RLock()
+	bots := g.Bots
+	g.mu.RUnlock()
+
+	var (
+		eg    errgroup.Group
+		total int
+	)
+	errs := make([]error, len(bots))
+	for i, bot := range bots {
+		if bot == nil {
+			continue
+		}
+		total++
+		i, bot := i, bot
+		eg.Go(func() error {
+			if err := bot.SendWithContext(ctx, msg, handlers...); err != nil {
+				errs[i] = fmt.Errorf("dingtalk: bot %q: %w", bot.Name, err)
+			}
+			return errs[i]
+		})
+	}
+	// Wait 只返回第一个错误，所有错误从 errs 中收集
+	eg.Wait()
+	e := &BotGroupError{total: total}
+	for _, err := range errs {
+		if err != nil {
+			e.Errs = append(e.Errs, err)
+		}
+	}
+	if len(e.Errs) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Send 使用机器人组中的所有机器人并发发送消息，详见 SendWithContext
+func (g *BotGroup) Send(msg Msg, handlers ...SendHandler) error {
+	return g.SendWithContext(context.Background(), msg, handlers...)
+}
+
+// SendTextWithContext 携带上下文并发发送文本类型消息
+func (g *BotGroup) SendTextWithContext(ctx context.Context, content string, handlers ...SendHandler) error {
+	return g.SendWithContext(ctx, Text{Content: content}, handlers...)
+}
+
+// SendText 并发发送文本类型消息
+func (g *BotGroup) SendText(content string, handlers ...SendHandler) error {
+	return g.SendTextWithContext(context.Background(), content, handlers...)
+}
+
+// SendLinkWithContext 携带上下文并发发送链接类型消息
+func (g *BotGroup) SendLinkWithContext(ctx context.Context, title, text, msgURL, picURL string, handlers ...SendHandler) error {
+	return g.SendWithContext(ctx, Link{Title: title, Text: text, MessageURL: msgURL, PicURL: picURL}, handlers...)
+}
+
+// SendLink 并发发送链接类型消息
+func (g *BotGroup) SendLink(title, text, msgURL, picURL string, handlers ...SendHandler) error {
+	return g.SendLinkWithContext(context.Background(), title, text, msgURL, picURL, handlers...)
+}
+
+// SendMarkdownWithContext 携带上下文并发发送 markdown 类型消息
+func (g *BotGroup) SendMarkdownWithContext(ctx context.Context, title, text string, handlers ...SendHandler) error {
+	return g.SendWithContext(ctx, Markdown{Title: title, Text: text}, handlers...)
+}
+
+// SendMarkdown 并发发送 markdown 类型消息
+func (g *BotGroup) SendMarkdown(title, text string, handlers ...SendHandler) error {
+	return g.SendMarkdownWithContext(context.Background(), title, text, handlers...)
+}
+
+// SendActionCardWithContext 携带上下文并发发送整体跳转 actionCard 类型消息
+func (g *BotGroup) SendActionCardWithContext(ctx context.Context, title, text, singleTitle, singleURL string, handlers ...SendHandler) error {
+	return g.SendWithContext(ctx, ActionCard{Title: title, Text: text, SingleTitle: singleTitle, SingleURL: singleURL}, handlers...)
+}
+
+// SendActionCard 并发发送整体跳转 actionCard 类型消息
+func (g *BotGroup) SendActionCard(title, text, singleTitle, singleURL string, handlers ...SendHandler) error {
+	return g.SendActionCardWithContext(context.Background(), title, text, singleTitle, singleURL, handlers...)
+}
+
+// SendActionsCardWithContext 携带上下文并发发送独立跳转 actionCard 类型消息
+func (g *BotGroup) SendActionsCardWithContext(ctx context.Context, title, text string, btns []ActionCardBtn, handlers ...SendHandler) error {
+	return g.SendWithContext(ctx, ActionsCard{Title: title, Text: text, Btns: btns}, handlers...)
+}
+
+// SendActionsCard 并发发送独立跳转 actionCard 类型消息
+func (g *BotGroup) SendActionsCard(title, text string, btns []ActionCardBtn, handlers ...SendHandler) error {
+	return g.SendActionsCardWithContext(context.Background(), title, text, btns, handlers...)
+}
+
+// SendFeedCardWithContext 携带上下文并发发送 feedCard 类型消息
+func (g *BotGroup) SendFeedCardWithContext(ctx context.Context, links []FeedCardLink, handlers ...SendHandler) error {
+	return g.SendWithContext(ctx, FeedCard{Links: links}, handlers...)
+}
+
+// SendFeedCard 并发发送 feedCard 类型消息
+func (g *BotGroup) SendFeedCard(links []FeedCardLink, handlers ...SendHandler) error {
+	return g.SendFeedCardWithContext(context.Background(), links, handlers...)
+}
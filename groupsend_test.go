@@ -0,0 +1,80 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBotGroupSend(t *testing.T) {
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	release := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(release)
+	}()
+	// 两个服务器都收到请求后才回复，只有并发发送才能在超时前完成
+	wait := func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+		}
+		replyError(0, "ok")(w, r)
+	}
+	staging, production := newTestServer(t), newTestServer(t)
+	staging.SetHandler(wait)
+	production.SetHandler(wait)
+	g := NewBotGroup(staging.Bot(), nil, production.Bot())
+
+	start := time.Now()
+	if err := g.SendText("deploy", AtAll); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("SendText() took %s, want the bots to send concurrently", elapsed)
+	}
+	for name, srv := range map[string]*testServer{"staging": staging, "production": production} {
+		r := srv.Last(t)
+		if got := r.JSON(t)["text"].(map[string]any)["content"]; got != "deploy" {
+			t.Fatalf("%s content = %v, want deploy", name, got)
+		}
+		if sentAt(t, r)["isAtAll"] != true {
+			t.Fatalf("%s did not receive the handler's at", name)
+		}
+	}
+	if err := NewBotGroup().Send(Text{Content: "empty"}); err != nil {
+		t.Fatalf("Send() on an empty group = %v, want nil", err)
+	}
+}
+
+func TestBotGroupError(t *testing.T) {
+	ok, bad := newTestServer(t), newTestServer(t)
+	bad.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	okBot, badBot, otherBad := ok.Bot(), bad.Bot(), bad.Bot()
+	badBot.Name, otherBad.Name = "bad", "other"
+	g := NewBotGroup(badBot, okBot, nil, otherBad)
+
+	err := g.SendWithContext(context.Background(), Text{Content: "fan out"})
+	var e *BotGroupError
+	if !errors.As(err, &e) || len(e.Errs) != 2 {
+		t.Fatalf("SendWithContext() = %v, want a BotGroupError with 2 errors", err)
+	}
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("SendWithContext() = %v, want ErrInvalidToken", err)
+	}
+	if !strings.HasPrefix(err.Error(), "dingtalk: 2 of 3 sends failed: ") {
+		t.Fatalf("Error() = %q, want a summary of 2 of 3", err)
+	}
+	if !strings.Contains(e.Errs[0].Error(), `bot "bad"`) || !strings.Contains(e.Errs[1].Error(), `bot "other"`) {
+		t.Fatalf("Errs = %v, want errors in group order with bot names", e.Errs)
+	}
+	if n := len(ok.Requests()); n != 1 {
+		t.Fatalf("ok bot sent %d requests, want the failures not to stop it", n)
+	}
+}
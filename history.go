@@ -0,0 +1,80 @@
+package dingtalk
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistoryCap 默认的发送记录容量
+const DefaultHistoryCap = 100
+
+// SentRecord 一次发送的记录
+type SentRecord struct {
+	Msg       Msg
+	At        At
+	Timestamp time.Time
+	Latency   time.Duration
+	Err       error
+	TraceID   string
+}
+
+// sentHistory 固定容量的环形缓冲区，容量已满时覆盖最早的记录
+type sentHistory struct {
+	mu      sync.Mutex
+	records []SentRecord
+	next    int
+	full    bool
+}
+
+// add 添加一条记录
+func (h *sentHistory) add(record SentRecord) {
+	h.mu.Lock()
+	h.records[h.next] = record
+	h.next++
+	if h.next == len(h.records) {
+		h.next = 0
+		h.full = true
+	}
+	h.mu.Unlock()
+}
+
+// last 按时间先后顺序返回最近的 limit 条记录，值不为正时返回全部记录
+func (h *sentHistory) last(limit int) []SentRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var records []SentRecord
+	if h.full {
+		records = append(records, h.records[h.next:]...)
+	}
+	records = append(records, h.records[:h.next]...)
+	if limit > 0 && limit < len(records) {
+		records = records[len(records)-limit:]
+	}
+	return records
+}
+
+// EnableHistory 开启发送记录，容量为正时会覆盖 HistoryCap ，重复调用会清空已有记录
+func (b *Bot) EnableHistory(size int) *Bot {
+	if b == nil {
+		return nil
+	}
+	if size > 0 {
+		b.HistoryCap = size
+	}
+	if b.HistoryCap <= 0 {
+		b.HistoryCap = DefaultHistoryCap
+	}
+	b.history = &sentHistory{records: make([]SentRecord, b.HistoryCap)}
+	return b
+}
+
+// SentHistory 按时间先后顺序返回最近的 limit 条发送记录，值不为正时返回全部记录，未开启发送记录时返回空
+func (b *Bot) SentHistory(limit int) []SentRecord {
+	if b == nil {
+		return nil
+	}
+	if b.history == nil {
+		return nil
+	}
+	return b.history.last(limit)
+}
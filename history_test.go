@@ -0,0 +1,72 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSentHistory(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().EnableHistory(3)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := bot.SendWithContext(ctx, Text{Content: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records := bot.SentHistory(0)
+	if len(records) != 3 {
+		t.Fatalf("SentHistory(0) = %d records, want 3", len(records))
+	}
+	for i, r := range records {
+		if want := fmt.Sprint(i + 2); r.Msg.(Text).Content != want {
+			t.Errorf("record %d = %q, want %q", i, r.Msg.(Text).Content, want)
+		}
+		if r.Err != nil || r.Timestamp.IsZero() {
+			t.Errorf("record %d = %+v", i, r)
+		}
+	}
+	if last := bot.SentHistory(1); len(last) != 1 || last[0].Msg.(Text).Content != "4" {
+		t.Fatalf("SentHistory(1) = %v, want the latest record", last)
+	}
+
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	bot.SendWithContext(ctx, Text{Content: "fail"})
+	if last := bot.SentHistory(1); last[0].Err == nil {
+		t.Fatal("failed send recorded without error")
+	}
+}
+
+func TestSentHistoryDisabled(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	if err := bot.SendWithContext(context.Background(), Text{Content: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if records := bot.SentHistory(0); records != nil {
+		t.Fatalf("SentHistory() = %v without EnableHistory, want nil", records)
+	}
+	if bot.EnableHistory(0).HistoryCap != DefaultHistoryCap {
+		t.Fatalf("HistoryCap = %d, want %d", bot.HistoryCap, DefaultHistoryCap)
+	}
+}
+
+func TestSentHistoryConcurrent(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().EnableHistory(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bot.SendWithContext(context.Background(), Text{Content: fmt.Sprint(i)})
+			bot.SentHistory(5)
+		}(i)
+	}
+	wg.Wait()
+	if records := bot.SentHistory(0); len(records) != 10 {
+		t.Fatalf("SentHistory(0) = %d records, want 10", len(records))
+	}
+}
@@ -0,0 +1,105 @@
+package dingtalk
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// recoverHook 调用钩子函数，恢复并记录其中的恐慌，避免影响发送流程，记录器为空时使用 slog.Default()
+func recoverHook(logger *slog.Logger, name string, hook func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			if logger == nil {
+				logger = slog.Default()
+			}
+			logger.Error("dingtalk: recovered from panic in hook", slog.String("hook", name), slog.Any("panic", r))
+		}
+	}()
+	hook()
+}
+
+// callHook 调用钩子函数，恢复并使用机器人的记录器记录其中的恐慌
+func (b *Bot) callHook(name string, hook func()) {
+	recoverHook(b.logger(), name, hook)
+}
+
+// AfterSendFunc 机器人发送请求结束后调用的函数，发送失败时响应体为空
+type AfterSendFunc func(ctx context.Context, s *Send, resp *SendResponse, latency time.Duration, err error)
+
+// BotCallbacks 机器人发送前后调用的回调函数，为空的函数不会被调用，其中的恐慌会被恢复并记录
+type BotCallbacks struct {
+	// 发送请求前调用，此时所有处理器都已执行完毕，上下文与传入 SendWithContext 的相同
+	BeforeSend func(ctx context.Context, s *Send)
+
+	// 发送请求结束后调用，发送失败时响应体为空，可用于统计发送结果和耗时
+	AfterSend AfterSendFunc
+}
+
+// WithCallbacks 设置发送前后调用的回调函数，会替换之前设置的回调函数
+func (b *Bot) WithCallbacks(cbs BotCallbacks) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.Callbacks = cbs
+	return b
+}
+
+// AddAfterSendHook 注册发送请求结束后调用的钩子函数，在 Callbacks.AfterSend 之后按注册顺序调用，不会被 WithCallbacks 替换，
+// 用于指标收集器等扩展，其中的恐慌会被恢复并记录
+func (b *Bot) AddAfterSendHook(hook AfterSendFunc) *Bot {
+	if b == nil {
+		return nil
+	}
+	if hook == nil {
+		return b
+	}
+	b.mu.Lock()
+	b.afterSendHooks = append(b.afterSendHooks[:len(b.afterSendHooks):len(b.afterSendHooks)], hook)
+	b.mu.Unlock()
+	return b
+}
+
+// runAfterSendHooks 按注册顺序调用内部钩子函数
+func (b *Bot) runAfterSendHooks(ctx context.Context, api *Send, resp *SendResponse, latency time.Duration, err error) {
+	b.mu.RLock()
+	hooks := b.afterSendHooks
+	b.mu.RUnlock()
+	for _, hook := range hooks {
+		b.callHook("afterSendHook", func() { hook(ctx, api, resp, latency, err) })
+	}
+}
+
+// BeforeSendHook 发送请求前调用的钩子函数，此时所有处理器都已执行完毕
+type BeforeSendHook func(ctx context.Context, s *Send)
+
+// OnBeforeSend 注册发送请求前调用的钩子函数，一般由处理器调用，其中的恐慌会被恢复并记录
+func (s *Send) OnBeforeSend(hook BeforeSendHook) {
+	s.beforeSend = append(s.beforeSend, hook)
+}
+
+// runBeforeSend 按注册顺序调用发送请求前的钩子函数
+func (s *Send) runBeforeSend(ctx context.Context, logger *slog.Logger) {
+	for _, hook := range s.beforeSend {
+		recoverHook(logger, "Send.OnBeforeSend", func() { hook(ctx, s) })
+	}
+}
+
+// AfterSendHook 发送请求结束后调用的钩子函数，发送失败时响应体为空
+type AfterSendHook func(ctx context.Context, s *Send, resp *SendResponse, err error)
+
+// OnAfterSend 注册请求结束后调用的钩子函数，一般由处理器调用，其中的恐慌会被恢复并记录
+func (s *Send) OnAfterSend(hook AfterSendHook) {
+	s.afterSend = append(s.afterSend, hook)
+}
+
+// runAfterSend 按注册顺序调用请求结束后的钩子函数
+func (s *Send) runAfterSend(ctx context.Context, logger *slog.Logger, r SendResponse, err error) {
+	resp := &r
+	if err != nil {
+		resp = nil
+	}
+	for _, hook := range s.afterSend {
+		recoverHook(logger, "Send.OnAfterSend", func() { hook(ctx, s, resp, err) })
+	}
+}
@@ -0,0 +1,140 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestOnBeforeAfterSend(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "correlation-id")
+	var before, after context.Context
+	var gotResp *SendResponse
+	var sentBefore int
+	bot.OnBeforeSend = func(ctx context.Context, s *Send) {
+		before, sentBefore = ctx, len(srv.Requests())
+	}
+	bot.OnAfterSend = func(ctx context.Context, s *Send, resp *SendResponse, err error) {
+		after, gotResp = ctx, resp
+	}
+	if err := bot.SendWithContext(ctx, Text{Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if before != ctx || after != ctx {
+		t.Fatal("hooks did not receive the context passed to SendWithContext")
+	}
+	if sentBefore != 0 {
+		t.Fatal("OnBeforeSend called after the request")
+	}
+
+	// 链路追踪和超时创建的上下文不会传给钩子函数
+	bot.TraceAll(noop.NewTracerProvider().Tracer("test")).Timeout = time.Second
+	before, after = nil, nil
+	if err := bot.SendWithContext(ctx, Text{Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if before != ctx || after != ctx {
+		t.Fatal("hooks did not receive the context passed to SendWithContext with tracing and timeout enabled")
+	}
+	if gotResp == nil || gotResp.ErrCode != 0 {
+		t.Fatalf("OnAfterSend resp = %v, want successful response", gotResp)
+	}
+
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	var gotErr error
+	bot.OnAfterSend = func(ctx context.Context, s *Send, resp *SendResponse, err error) {
+		gotResp, gotErr = resp, err
+	}
+	bot.SendWithContext(ctx, Text{Content: "hello"})
+	if gotResp != nil || !errors.Is(gotErr, ErrInvalidToken) {
+		t.Fatalf("OnAfterSend resp, err = %v, %v, want nil and ErrInvalidToken", gotResp, gotErr)
+	}
+}
+
+func TestHookPanicRecovered(t *testing.T) {
+	srv := newTestServer(t)
+	var buf bytes.Buffer
+	bot := srv.Bot().WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	bot.OnBeforeSend = func(context.Context, *Send) { panic("before") }
+	bot.OnAfterSend = func(context.Context, *Send, *SendResponse, error) { panic("after") }
+	if err := bot.SendWithContext(context.Background(), Text{Content: "hello"}); err != nil {
+		t.Fatalf("SendWithContext() error = %v, hook panics should not be returned", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+	logs := buf.String()
+	if !strings.Contains(logs, `"hook":"OnBeforeSend"`) || !strings.Contains(logs, `"hook":"OnAfterSend"`) {
+		t.Fatalf("panics not logged: %s", logs)
+	}
+}
+
+func TestWithCallbacks(t *testing.T) {
+	srv := newTestServer(t)
+	var order []string
+	var gotLatency time.Duration
+	var gotResp *SendResponse
+	bot := srv.Bot().WithCallbacks(BotCallbacks{
+		BeforeSend: func(ctx context.Context, s *Send) { order = append(order, "Callbacks.BeforeSend") },
+		AfterSend: func(ctx context.Context, s *Send, resp *SendResponse, latency time.Duration, err error) {
+			order = append(order, "Callbacks.AfterSend")
+			gotResp, gotLatency = resp, latency
+		},
+	})
+	bot.OnBeforeSend = func(context.Context, *Send) { order = append(order, "OnBeforeSend") }
+	bot.OnAfterSend = func(context.Context, *Send, *SendResponse, error) { order = append(order, "OnAfterSend") }
+	if err := bot.SendText("hello"); err != nil {
+		t.Fatal(err)
+	}
+	want := "Callbacks.BeforeSend,OnBeforeSend,Callbacks.AfterSend,OnAfterSend"
+	if got := strings.Join(order, ","); got != want {
+		t.Fatalf("call order = %s, want %s", got, want)
+	}
+	if gotResp == nil || gotResp.ErrCode != 0 || gotLatency <= 0 {
+		t.Fatalf("AfterSend resp, latency = %v, %s, want a successful response and positive latency", gotResp, gotLatency)
+	}
+
+	var observed int
+	bot.AddAfterSendHook(func(context.Context, *Send, *SendResponse, time.Duration, error) { observed++ })
+	bot.OnBeforeSend, bot.OnAfterSend = nil, nil
+	if err := bot.WithCallbacks(BotCallbacks{}).SendText("zero"); err != nil {
+		t.Fatalf("SendText() with zero BotCallbacks = %v", err)
+	}
+	if len(order) != 4 {
+		t.Fatalf("WithCallbacks() did not replace the callbacks: %q", order)
+	}
+	if observed != 1 {
+		t.Fatalf("internal hook called %d times, want WithCallbacks to keep it", observed)
+	}
+	if (*Bot)(nil).WithCallbacks(BotCallbacks{}) != nil {
+		t.Fatal("WithCallbacks() on nil bot returned non-nil")
+	}
+}
+
+func TestAddAfterSendHook(t *testing.T) {
+	srv := newTestServer(t)
+	var order []string
+	bot := srv.Bot().
+		AddAfterSendHook(func(context.Context, *Send, *SendResponse, time.Duration, error) { order = append(order, "first") }).
+		AddAfterSendHook(nil).
+		AddAfterSendHook(func(context.Context, *Send, *SendResponse, time.Duration, error) { panic("second") }).
+		AddAfterSendHook(func(context.Context, *Send, *SendResponse, time.Duration, error) { order = append(order, "third") })
+	if err := bot.SendText("hooked"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "first,third" {
+		t.Fatalf("hooks called %s, want first,third in order with the panic recovered", got)
+	}
+	if (*Bot)(nil).AddAfterSendHook(nil) != nil {
+		t.Fatal("AddAfterSendHook() on nil bot returned non-nil")
+	}
+}
@@ -0,0 +1,126 @@
+package dingtalk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AtUser 接收消息中被@的用户
+type AtUser struct {
+	DingtalkID string `json:"dingtalkId"`        // 加密的用户 ID
+	StaffID    string `json:"staffId,omitempty"` // 企业内部群中被@的用户 userId
+}
+
+// IncomingMsg 机器人接收到的群消息，即用户@机器人后钉钉推送至消息接收地址的请求体
+type IncomingMsg struct {
+	// 消息 ID
+	MsgID string `json:"msgId"`
+
+	// 消息类型
+	MsgType MsgType `json:"msgtype"`
+
+	// 文本消息的内容
+	Text Text `json:"text"`
+
+	// 消息的创建时间，单位是毫秒
+	CreateAt int64 `json:"createAt"`
+
+	// 会话 ID
+	ConversationID string `json:"conversationId"`
+
+	// 会话类型，1：单聊，2：群聊
+	ConversationType string `json:"conversationType"`
+
+	// 群聊时的群名称
+	ConversationTitle string `json:"conversationTitle,omitempty"`
+
+	// 被@的用户列表
+	AtUsers []AtUser `json:"atUsers,omitempty"`
+
+	// 机器人是否在被@的列表中
+	IsInAtList bool `json:"isInAtList"`
+
+	// 机器人所属企业的 corpId
+	ChatbotCorpID string `json:"chatbotCorpId,omitempty"`
+
+	// 加密的机器人 ID
+	ChatbotUserID string `json:"chatbotUserId"`
+
+	// 机器人的编码
+	RobotCode string `json:"robotCode,omitempty"`
+
+	// 加密的发送者 ID
+	SenderID string `json:"senderId"`
+
+	// 发送者昵称
+	SenderNick string `json:"senderNick"`
+
+	// 企业内部群中发送者的 userId
+	SenderStaffID string `json:"senderStaffId,omitempty"`
+
+	// 企业内部群中发送者所属企业的 corpId
+	SenderCorpID string `json:"senderCorpId,omitempty"`
+
+	// 发送者是否为管理员
+	IsAdmin bool `json:"isAdmin"`
+
+	// 当前会话的 Webhook 地址，可直接用于回复消息
+	SessionWebhook string `json:"sessionWebhook"`
+
+	// 当前会话的 Webhook 地址过期时间，单位是毫秒
+	SessionWebhookExpiredTime int64 `json:"sessionWebhookExpiredTime"`
+}
+
+// DecodeIncomingMsg 从请求体中解析机器人接收到的群消息
+func DecodeIncomingMsg(r *http.Request) (*IncomingMsg, error) {
+	defer r.Body.Close()
+	m := &IncomingMsg{}
+	err := json.NewDecoder(r.Body).Decode(m)
+	if err != nil {
+		return nil, fmt.Errorf("dingtalk: failed to decode incoming msg: %w", err)
+	}
+	return m, nil
+}
+
+// IsAtMe 检测消息是否发送给编码为 robotCode 的机器人，且该机器人在被@的用户列表中
+func (m *IncomingMsg) IsAtMe(robotCode string) bool {
+	if m.RobotCode != robotCode {
+		return false
+	}
+	for _, user := range m.AtUsers {
+		if user.DingtalkID == m.ChatbotUserID {
+			return true
+		}
+	}
+	return m.IsInAtList
+}
+
+// SessionWebhookValid 检测当前会话的 Webhook 地址是否可用
+func (m *IncomingMsg) SessionWebhookValid() bool {
+	return m.SessionWebhook != "" && time.Now().UnixMilli() < m.SessionWebhookExpiredTime
+}
+
+// ErrSessionExpired 接收消息中会话的 Webhook 地址为空或已过期，无法回复至同一会话
+var ErrSessionExpired = errors.New("dingtalk: session webhook is empty or expired")
+
+// Reply 通过会话的 Webhook 地址回复消息至同一会话，地址不可用时返回 ErrSessionExpired ，
+// 此时可以改用 bot.Send 通过机器人自身的 Webhook 发送至机器人所在的群
+func (m *IncomingMsg) Reply(bot *Bot, msg Msg, handlers ...SendHandler) error {
+	if !m.SessionWebhookValid() {
+		return ErrSessionExpired
+	}
+	return bot.Send(msg, append(handlers[:len(handlers):len(handlers)], Webhook(m.SessionWebhook))...)
+}
+
+// ReplyText 回复文本类型消息至同一会话，发送接口不支持指定会话 ID ，会话由 SessionWebhook 确定，详见 Reply
+func (m *IncomingMsg) ReplyText(bot *Bot, content string) error {
+	return m.Reply(bot, Text{Content: content})
+}
+
+// ReplyMarkdown 回复 markdown 类型消息至同一会话，详见 ReplyText
+func (m *IncomingMsg) ReplyMarkdown(bot *Bot, title, text string) error {
+	return m.Reply(bot, Markdown{Title: title, Text: text})
+}
@@ -0,0 +1,123 @@
+package dingtalk
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// loadIncomingMsg 使用 testdata/incoming.json 中钉钉文档的示例请求体构造接收消息
+func loadIncomingMsg(t *testing.T) *IncomingMsg {
+	t.Helper()
+	data, err := os.ReadFile("testdata/incoming.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := DecodeIncomingMsg(httptest.NewRequest("POST", "/dingtalk", bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDecodeIncomingMsg(t *testing.T) {
+	m := loadIncomingMsg(t)
+	if m.MsgType != MsgText || m.Text.Content != " 你好" {
+		t.Fatalf("msg = %s %q, want text \" 你好\"", m.MsgType, m.Text.Content)
+	}
+	if m.MsgID != "msgdY+40xxxxxxxxxxxxgn4gg==" || m.ConversationID != "cidZrSVBxxxxxxxxxx0ZgO8w==" ||
+		m.SenderStaffID != "manager4220" || m.SenderNick != "小钉" || m.ConversationType != "2" ||
+		m.CreateAt != 1613630252678 || !m.IsAdmin || len(m.AtUsers) != 2 || m.AtUsers[1].StaffID != "manager4220" {
+		t.Fatalf("DecodeIncomingMsg() = %+v", m)
+	}
+	if !m.IsAtMe("dingxxxxxxxxxxxxxxxx") {
+		t.Fatal("IsAtMe() = false, want true")
+	}
+	if m.IsAtMe("other") {
+		t.Fatal("IsAtMe(other) = true, want false")
+	}
+	if m.SessionWebhookValid() {
+		t.Fatal("SessionWebhookValid() = true for expired sample")
+	}
+
+	_, err := DecodeIncomingMsg(httptest.NewRequest("POST", "/dingtalk", bytes.NewReader([]byte("{"))))
+	if err == nil {
+		t.Fatal("DecodeIncomingMsg() error = nil for invalid body")
+	}
+}
+
+func TestIncomingMsgReply(t *testing.T) {
+	srv := newTestServer(t)
+	m := loadIncomingMsg(t)
+	m.SessionWebhook = srv.URL + "/robot/sendBySession?session=xxx"
+	m.SessionWebhookExpiredTime = time.Now().Add(time.Hour).UnixMilli()
+	bot := &Bot{Token: testToken, BaseURL: "http://127.0.0.1:0"}
+	if err := m.Reply(bot, Text{Content: "pong"}); err != nil {
+		t.Fatal(err)
+	}
+	last := srv.Last(t)
+	if got := last.Query.Get("session"); got != "xxx" {
+		t.Fatalf("session = %q, want xxx", got)
+	}
+	if last.Query.Has("access_token") {
+		t.Fatal("reply through session webhook should not carry access_token")
+	}
+
+	// 容量有余的处理器切片不会被写入
+	handlers := make([]SendHandler, 1, 2)
+	handlers[0] = AtAll
+	spare := handlers[:2]
+	if err := m.Reply(bot, Text{Content: "pong"}, handlers...); err != nil {
+		t.Fatal(err)
+	}
+	if spare[1] != nil {
+		t.Fatal("Reply() wrote the Webhook handler into the caller's slice")
+	}
+}
+
+func TestIncomingMsgReplyExpired(t *testing.T) {
+	srv := newTestServer(t)
+	m := loadIncomingMsg(t)
+	if err := m.Reply(srv.Bot(), Text{Content: "pong"}); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("Reply() error = %v, want ErrSessionExpired", err)
+	}
+	m.SessionWebhook = ""
+	m.SessionWebhookExpiredTime = time.Now().Add(time.Hour).UnixMilli()
+	if err := m.ReplyText(srv.Bot(), "pong"); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("ReplyText() error = %v, want ErrSessionExpired", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("server received %d requests, want 0", n)
+	}
+}
+
+func TestIncomingMsgReplyText(t *testing.T) {
+	srv := newTestServer(t)
+	m := loadIncomingMsg(t)
+	m.SessionWebhook = srv.URL + "/robot/sendBySession?session=xxx"
+	m.SessionWebhookExpiredTime = time.Now().Add(time.Hour).UnixMilli()
+	bot := &Bot{Token: testToken, BaseURL: "http://127.0.0.1:0"}
+
+	if err := m.ReplyText(bot, "收到"); err != nil {
+		t.Fatalf("ReplyText() = %v", err)
+	}
+	body := srv.Last(t).JSON(t)
+	if body["msgtype"] != "text" || body["text"].(map[string]any)["content"] != "收到" {
+		t.Fatalf("ReplyText() sent %v", body)
+	}
+
+	if err := m.ReplyMarkdown(bot, "标题", "**正文**"); err != nil {
+		t.Fatalf("ReplyMarkdown() = %v", err)
+	}
+	body = srv.Last(t).JSON(t)
+	markdown, _ := body["markdown"].(map[string]any)
+	if body["msgtype"] != "markdown" || markdown["title"] != "标题" || markdown["text"] != "**正文**" {
+		t.Fatalf("ReplyMarkdown() sent %v", body)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Fatalf("server received %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,47 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBotInterceptor(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	var order []string
+	bot.OnBeforeSend = func(context.Context, *Send) { order = append(order, "OnBeforeSend") }
+	bot.Interceptor = func(r *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		order = append(order, "Interceptor")
+		r.Header.Set("X-Auth", "signed")
+		return next(r)
+	}
+	if err := bot.SendText("modified"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "OnBeforeSend,Interceptor" {
+		t.Fatalf("call order = %s, want OnBeforeSend,Interceptor", got)
+	}
+	if got := srv.Last(t).Header.Get("X-Auth"); got != "signed" {
+		t.Fatalf("X-Auth = %q, want the interceptor's header", got)
+	}
+
+	bot.Interceptor = func(r *http.Request, _ func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"errcode":300001,"errmsg":"replaced"}`)),
+			Request:    r,
+		}, nil
+	}
+	if err := bot.SendText("replaced"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("SendText() = %v, want the replaced response's ErrInvalidToken", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want the replaced one skipped", n)
+	}
+}
@@ -0,0 +1,132 @@
+package dingtalk
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// KeywordInjection 一次关键词自动添加的记录
+type KeywordInjection struct {
+	Timestamp time.Time
+	Keyword   string
+	MsgType   MsgType
+	Field     string
+}
+
+// logKeywordInjection 开启记录时追加一条关键词自动添加记录
+func (b *Bot) logKeywordInjection(keyword string, msgType MsgType, field string) {
+	if !b.LogKeywordInjections {
+		return
+	}
+	b.keywordMu.Lock()
+	b.KeywordLog = append(b.KeywordLog, KeywordInjection{Timestamp: time.Now(), Keyword: keyword, MsgType: msgType, Field: field})
+	b.keywordMu.Unlock()
+}
+
+// discardKeywordInjection 不记录关键词添加，用于估算请求体等不会发送的场景
+func discardKeywordInjection(string, MsgType, string) {}
+
+// ClearKeywordLog 清空关键词自动添加记录
+func (b *Bot) ClearKeywordLog() {
+	if b == nil {
+		return
+	}
+	b.keywordMu.Lock()
+	b.KeywordLog = nil
+	b.keywordMu.Unlock()
+}
+
+// KeywordInjector 自定义关键词添加策略，返回添加关键词后的文本，keywords 为机器人当前的关键词
+type KeywordInjector interface {
+	Inject(text string, keywords []string) string
+}
+
+// injectCustomKeyword 使用自定义策略为内置类型消息添加关键词，检测的字段与内置策略相同：
+// 带标题的消息将标题和正文以换行连接后交给策略，feedCard 类型消息连接所有内容的标题，
+// 策略在末尾追加的内容会添加至正文或最后一条内容的标题，策略改写了其他部分时只对最后一个字段调用策略
+func injectCustomKeyword(injector KeywordInjector, keywords []string, msg Msg, record func(keyword string, msgType MsgType, field string)) Msg {
+	inject := func(msgType MsgType, field string, fields ...string) string {
+		last := fields[len(fields)-1]
+		joined := strings.Join(fields, "\n")
+		out := injector.Inject(joined, keywords)
+		if out == joined {
+			return last
+		}
+		result := last
+		if suffix, ok := strings.CutPrefix(out, joined); ok {
+			result += suffix
+		} else {
+			result = injector.Inject(last, keywords)
+		}
+		if result != last {
+			record(strings.TrimPrefix(result, last), msgType, field)
+		}
+		return result
+	}
+	switch m := msg.(type) {
+	case Text:
+		m.Content = inject(m.Type(), "Content", m.Content)
+		return m
+	case Link:
+		m.Text = inject(m.Type(), "Text", m.Title, m.Text)
+		return m
+	case Markdown:
+		m.Text = inject(m.Type(), "Text", m.Title, m.Text)
+		return m
+	case ActionCard:
+		m.Text = inject(m.Type(), "Text", m.Title, m.Text)
+		return m
+	case ActionsCard:
+		m.Text = inject(m.Type(), "Text", m.Title, m.Text)
+		return m
+	case FeedCard:
+		if len(m.Links) == 0 {
+			return m
+		}
+		titles := make([]string, len(m.Links))
+		for i, link := range m.Links {
+			titles[i] = link.Title
+		}
+		// 拷贝一份内容，避免修改调用者的切片
+		m.Links = append([]FeedCardLink(nil), m.Links...)
+		last := &m.Links[len(m.Links)-1]
+		last.Title = inject(m.Type(), "Links.Title", titles...)
+		return m
+	default:
+		return msg
+	}
+}
+
+// severityKeywordInjector 根据文本中的严重程度标记选择关键词
+type severityKeywordInjector struct {
+	tokens   []string
+	keywords map[string][]string
+}
+
+// NewSeverityKeywordInjector 新建根据严重程度选择关键词的策略，文本包含某个严重程度标记时使用其对应的关键词，
+// 多个标记同时出现时按标记的字典序选择第一个，没有标记时使用机器人的关键词，文本不包含任何一个关键词时在末尾添加第一个关键词
+func NewSeverityKeywordInjector(severityKeywords map[string][]string) KeywordInjector {
+	s := &severityKeywordInjector{keywords: make(map[string][]string, len(severityKeywords))}
+	for token, keywords := range severityKeywords {
+		s.tokens = append(s.tokens, token)
+		s.keywords[token] = append([]string(nil), keywords...)
+	}
+	sort.Strings(s.tokens)
+	return s
+}
+
+func (s *severityKeywordInjector) Inject(text string, keywords []string) string {
+	for _, token := range s.tokens {
+		if strings.Contains(text, token) {
+			keywords = s.keywords[token]
+			break
+		}
+	}
+	if len(keywords) == 0 || containsAnyKeyword(keywords, text) {
+		return text
+	}
+	return text + keywords[0]
+}
+
+var _ KeywordInjector = (*severityKeywordInjector)(nil)
@@ -0,0 +1,231 @@
+package dingtalk
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKeywordLog(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Keywords = []string{"告警", "通知"}
+	bot.LogKeywordInjections = true
+	ctx := context.Background()
+	for _, msg := range []Msg{
+		Text{Content: "磁盘已满"},
+		Text{Content: "磁盘告警"},
+		Markdown{Title: "磁盘", Text: "### 已满"},
+	} {
+		if err := bot.SendWithContext(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(bot.KeywordLog) != 2 {
+		t.Fatalf("KeywordLog = %+v, want 2 entries", bot.KeywordLog)
+	}
+	for i, want := range []struct {
+		msgType MsgType
+		field   string
+	}{{MsgText, "Content"}, {MsgMarkdown, "Text"}} {
+		e := bot.KeywordLog[i]
+		if e.Keyword != "告警" || e.MsgType != want.msgType || e.Field != want.field || e.Timestamp.IsZero() {
+			t.Errorf("KeywordLog[%d] = %+v, want 告警 in %s.%s", i, e, want.msgType, want.field)
+		}
+	}
+	bot.ClearKeywordLog()
+	if len(bot.KeywordLog) != 0 {
+		t.Fatalf("KeywordLog = %+v after ClearKeywordLog", bot.KeywordLog)
+	}
+
+	bot.LogKeywordInjections = false
+	if err := bot.SendWithContext(ctx, Text{Content: "磁盘已满"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(bot.KeywordLog) != 0 {
+		t.Fatal("KeywordLog populated with LogKeywordInjections disabled")
+	}
+}
+
+func TestKeywordLogConcurrent(t *testing.T) {
+	bot := &Bot{Token: TestWebhookToken, Keywords: []string{"告警"}, LogKeywordInjections: true}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bot.SendWithContext(context.Background(), Text{Content: "磁盘已满"})
+		}()
+	}
+	wg.Wait()
+	if len(bot.KeywordLog) != 10 {
+		t.Fatalf("KeywordLog has %d entries, want 10", len(bot.KeywordLog))
+	}
+}
+
+func TestKeywordsConcurrent(t *testing.T) {
+	bot := &Bot{Token: TestWebhookToken}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			bot.AppendKeywords("告警")
+		}()
+		go func() {
+			defer wg.Done()
+			bot.ContainsAnyKeyword("磁盘告警")
+		}()
+		go func() {
+			defer wg.Done()
+			bot.SendWithContext(context.Background(), Text{Content: "磁盘已满"})
+		}()
+	}
+	wg.Wait()
+	if n := len(bot.keywords()); n != 20 {
+		t.Fatalf("got %d keywords, want 20", n)
+	}
+}
+
+func TestSetKeywords(t *testing.T) {
+	bot := &Bot{}
+	kws := []string{"a", "b"}
+	bot.SetKeywords(kws...)
+	kws[0] = "changed"
+	if !reflect.DeepEqual(bot.keywords(), []string{"a", "b"}) {
+		t.Fatalf("keywords = %q, SetKeywords should copy its input", bot.keywords())
+	}
+	bot.AppendKeywords("c", "a")
+	bot.RemoveKeyword("a")
+	if !reflect.DeepEqual(bot.keywords(), []string{"b", "c"}) {
+		t.Fatalf("keywords = %q, want [b c]", bot.keywords())
+	}
+	if bot.ContainsAnyKeyword("a") || !bot.ContainsAnyKeyword("abc") {
+		t.Fatal("ContainsAnyKeyword() does not match the current keywords")
+	}
+}
+
+func TestAddKeyword(t *testing.T) {
+	bot := &Bot{}
+	if !bot.AddKeyword("告警").AddKeyword("通知").ContainsAnyKeyword("磁盘告警") {
+		t.Fatal("ContainsAnyKeyword() does not match an added keyword")
+	}
+	bot.AddKeyword("告警").RemoveKeyword("告警")
+	if !reflect.DeepEqual(bot.keywords(), []string{"通知"}) {
+		t.Fatalf("keywords = %q, want [通知]", bot.keywords())
+	}
+	if bot.ContainsAnyKeyword("磁盘告警") {
+		t.Fatal("ContainsAnyKeyword() matches a removed keyword")
+	}
+	if (*Bot)(nil).AddKeyword("a") != nil || (*Bot)(nil).RemoveKeyword("a") != nil {
+		t.Fatal("AddKeyword() or RemoveKeyword() on nil bot returned non-nil")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			bot.AddKeyword("临时")
+		}()
+		go func() {
+			defer wg.Done()
+			bot.RemoveKeyword("不存在")
+		}()
+	}
+	wg.Wait()
+	if n := len(bot.RemoveKeyword("临时").keywords()); n != 1 {
+		t.Fatalf("got %d keywords after removing all copies, want 1", n)
+	}
+}
+
+// suffixInjector 记录调用并在文本末尾追加固定内容的策略
+type suffixInjector struct {
+	calls []string
+}
+
+func (s *suffixInjector) Inject(text string, keywords []string) string {
+	s.calls = append(s.calls, text)
+	if containsAnyKeyword(keywords, text) {
+		return text
+	}
+	return text + "[" + keywords[0] + "]"
+}
+
+func TestKeywordInjector(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Keywords = []string{"告警"}
+	injector := &suffixInjector{}
+	bot.KeywordInjector = injector
+
+	if err := bot.Send(Markdown{Title: "磁盘", Text: "已满"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["markdown"].(map[string]any)["text"]; got != "已满[告警]" {
+		t.Fatalf("markdown text = %v, want custom suffix", got)
+	}
+	if len(injector.calls) != 1 || injector.calls[0] != "磁盘\n已满" {
+		t.Fatalf("injector calls = %q, want title and text", injector.calls)
+	}
+
+	for _, msg := range []Msg{
+		Link{Title: "告警", Text: "磁盘已满", MessageURL: "https://example.com"},
+		Markdown{Title: "告警", Text: "磁盘已满"},
+		ActionCard{Title: "告警", Text: "磁盘已满", SingleTitle: "more", SingleURL: "https://example.com"},
+		ActionsCard{Title: "告警", Text: "磁盘已满", Btns: []ActionCardBtn{{Title: "a", ActionURL: "https://example.com"}}},
+	} {
+		data, err := bot.Serialize(context.Background(), msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "[告警]") {
+			t.Errorf("%T: keyword in title ignored, payload %s", msg, data)
+		}
+	}
+
+	feed := FeedCard{Links: []FeedCardLink{{Title: "告警汇总", MessageURL: "https://example.com"}, {Title: "磁盘", MessageURL: "https://example.com"}}}
+	if data, _ := bot.Serialize(context.Background(), feed); strings.Contains(string(data), "[告警]") {
+		t.Errorf("feedCard keyword in first link ignored, payload %s", data)
+	}
+	feed.Links[0].Title = "汇总"
+	data, _ := bot.Serialize(context.Background(), feed)
+	if !strings.Contains(string(data), `"title":"磁盘[告警]"`) {
+		t.Errorf("feedCard payload %s, want suffix on the last link", data)
+	}
+	if feed.Links[1].Title != "磁盘" {
+		t.Fatal("injector modified the caller's links")
+	}
+}
+
+func TestSeverityKeywordInjector(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Keywords = []string{"通知"}
+	bot.KeywordInjector = NewSeverityKeywordInjector(map[string][]string{
+		"P0": {"紧急告警"},
+		"P1": {"告警"},
+	})
+	for content, want := range map[string]string{
+		"P0 数据库宕机":   "P0 数据库宕机紧急告警",
+		"P1 磁盘已满":    "P1 磁盘已满告警",
+		"P1 磁盘告警":    "P1 磁盘告警",
+		"日报":         "日报通知",
+		"P0 P1 同时出现": "P0 P1 同时出现紧急告警",
+	} {
+		if err := bot.SendText(content); err != nil {
+			t.Fatal(err)
+		}
+		if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != want {
+			t.Errorf("content %q = %v, want %q", content, got, want)
+		}
+	}
+	if err := bot.Send(Markdown{Title: "P0 数据库", Text: "宕机"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["markdown"].(map[string]any)["text"]; got != "宕机紧急告警" {
+		t.Fatalf("markdown text = %v, want severity from the title", got)
+	}
+}
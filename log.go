@@ -0,0 +1,52 @@
+package dingtalk
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// WithLogger 设置结构化日志记录器
+func (b *Bot) WithLogger(l *slog.Logger) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.SlogLogger = l
+	return b
+}
+
+// logger 返回用于记录发送日志的记录器，未设置且未开启调试时返回空
+func (b *Bot) logger() *slog.Logger {
+	if b == nil {
+		return nil
+	}
+	if b.SlogLogger != nil {
+		return b.SlogLogger
+	}
+	if os.Getenv("DINGTALK_DEBUG") == "1" {
+		return slog.Default()
+	}
+	return nil
+}
+
+// logSend 记录一次发送，发送失败时使用 slog.LevelError 级别
+func (b *Bot) logSend(ctx context.Context, api *Send, r SendResponse, latency time.Duration, err error) {
+	logger := b.logger()
+	if logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("msg_type", string(msgTypeOf(api.Msg))),
+		slog.Bool("at_all", api.At.IsAtAll),
+		slog.Int64("latency_ms", latency.Milliseconds()),
+		slog.Int("err_code", r.ErrCode),
+		slog.String("token_prefix", maskToken(api.AccessToken)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelError, "dingtalk: failed to send message", attrs...)
+		return
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "dingtalk: message sent", attrs...)
+}
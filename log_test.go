@@ -0,0 +1,76 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// decodeLogs 解析 JSON 格式的日志
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var logs []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			t.Fatal(err)
+		}
+		logs = append(logs, m)
+	}
+	return logs
+}
+
+func TestWithLogger(t *testing.T) {
+	srv := newTestServer(t)
+	var buf bytes.Buffer
+	bot := srv.Bot().WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	if err := bot.SendWithContext(context.Background(), Text{Content: "hello"}, AtAll); err != nil {
+		t.Fatal(err)
+	}
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(logs))
+	}
+	log := logs[0]
+	if log["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO", log["level"])
+	}
+	if log["msg_type"] != "text" {
+		t.Errorf("msg_type = %v, want text", log["msg_type"])
+	}
+	if log["at_all"] != true {
+		t.Errorf("at_all = %v, want true", log["at_all"])
+	}
+	if log["err_code"] != 0.0 {
+		t.Errorf("err_code = %v, want 0", log["err_code"])
+	}
+	if log["token_prefix"] != testToken[:8]+"***" {
+		t.Errorf("token_prefix = %v, want %q", log["token_prefix"], testToken[:8]+"***")
+	}
+	if _, ok := log["latency_ms"]; !ok {
+		t.Error("missing latency_ms")
+	}
+}
+
+func TestWithLoggerError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	var buf bytes.Buffer
+	bot := srv.Bot().WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	if err := bot.SendWithContext(context.Background(), Text{Content: "hello"}); err == nil {
+		t.Fatal("SendWithContext() error = nil, want error")
+	}
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(logs))
+	}
+	if logs[0]["level"] != "ERROR" {
+		t.Errorf("level = %v, want ERROR", logs[0]["level"])
+	}
+	if logs[0]["err_code"] != float64(ErrInvalidToken.Code) {
+		t.Errorf("err_code = %v, want %d", logs[0]["err_code"], ErrInvalidToken.Code)
+	}
+}
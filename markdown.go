@@ -0,0 +1,116 @@
+package dingtalk
+
+import "strings"
+
+// escapeTableCell 转义表格单元格中的竖线和换行符
+func escapeTableCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "|", `\|`)
+	return strings.ReplaceAll(cell, "\n", " ")
+}
+
+// MarkdownTable 生成 Markdown 表格，行中单元格数量不足时补空，超出表头数量的单元格会被忽略
+func MarkdownTable(headers []string, rows [][]string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for i := range headers {
+			var cell string
+			if i < len(cells) {
+				cell = escapeTableCell(cells[i])
+			}
+			sb.WriteString(" ")
+			sb.WriteString(cell)
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(headers)
+	sb.WriteString("|")
+	sb.WriteString(strings.Repeat(" --- |", len(headers)))
+	sb.WriteString("\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+	return sb.String()
+}
+
+// AppendSection 在正文末尾添加一个二级标题及其内容，返回新的消息
+func (m Markdown) AppendSection(heading, content string) Markdown {
+	m.Text += "## " + heading + "\n\n" + content + "\n\n"
+	return m
+}
+
+// AppendCode 在正文末尾添加一个代码块，返回新的消息
+func (m Markdown) AppendCode(lang, code string) Markdown {
+	m.Text += "```" + lang + "\n" + strings.TrimSuffix(code, "\n") + "\n```\n\n"
+	return m
+}
+
+// AppendTable 在正文末尾添加一个表格，返回新的消息，详见 MarkdownTable
+func (m Markdown) AppendTable(headers []string, rows [][]string) Markdown {
+	m.Text += MarkdownTable(headers, rows) + "\n"
+	return m
+}
+
+// AppendRaw 在正文末尾直接添加文本，返回新的消息
+func (m Markdown) AppendRaw(text string) Markdown {
+	m.Text += text
+	return m
+}
+
+// Prepend 在正文开头直接添加文本，返回新的消息
+func (m Markdown) Prepend(text string) Markdown {
+	m.Text = text + m.Text
+	return m
+}
+
+// Wrap 在正文开头和末尾分别添加前缀和后缀，返回新的消息
+func (m Markdown) Wrap(prefix, suffix string) Markdown {
+	m.Text = prefix + m.Text + suffix
+	return m
+}
+
+// MarkdownSanitizer 在会被钉钉解析为 markdown 语法的字符前添加反斜杠，
+// 方括号、反引号、星号、下划线和竖线总是转义，井号和大于号只在行首（忽略前导空白）时转义，已被转义的字符不会重复转义
+func MarkdownSanitizer(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	lineStart, escaped := true, false
+	for _, r := range s {
+		switch {
+		case escaped:
+		case r == '[' || r == ']' || r == '`' || r == '*' || r == '_' || r == '|':
+			sb.WriteByte('\\')
+		case (r == '#' || r == '>') && lineStart:
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+		escaped = !escaped && r == '\\'
+		switch r {
+		case '\n':
+			lineStart = true
+		case ' ', '\t':
+		default:
+			lineStart = false
+		}
+	}
+	return sb.String()
+}
+
+// sanitizeMsg 使用 MarkdownSanitizer 转义文本类型和 markdown 类型消息的所有字符串字段，其他类型的消息原样返回
+func sanitizeMsg(msg Msg) Msg {
+	switch m := msg.(type) {
+	case Text:
+		m.Content = MarkdownSanitizer(m.Content)
+		return m
+	case Markdown:
+		m.Title = MarkdownSanitizer(m.Title)
+		m.Text = MarkdownSanitizer(m.Text)
+		return m
+	default:
+		return msg
+	}
+}
@@ -0,0 +1,168 @@
+package dingtalk
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMarkdownTable(t *testing.T) {
+	got := MarkdownTable([]string{"name", "value"}, [][]string{{"a|b", "1\n2"}, {"short"}, {"x", "y", "ignored"}})
+	want := "| name | value |\n| --- | --- |\n| a\\|b | 1 2 |\n| short |  |\n| x | y |\n"
+	if got != want {
+		t.Fatalf("MarkdownTable() = %q, want %q", got, want)
+	}
+	if got := MarkdownTable(nil, [][]string{{"a"}}); got != "" {
+		t.Fatalf("MarkdownTable(nil) = %q, want empty", got)
+	}
+}
+
+func TestMarkdownAppend(t *testing.T) {
+	base := Markdown{Title: "日报", Text: "# 日报\n\n"}
+	section := base.AppendSection("系统", "运行正常")
+	if base.Text != "# 日报\n\n" {
+		t.Fatalf("AppendSection modified receiver: %q", base.Text)
+	}
+	if want := base.Text + "## 系统\n\n运行正常\n\n"; section.Text != want {
+		t.Fatalf("AppendSection() = %q, want %q", section.Text, want)
+	}
+	code := section.AppendCode("go", "fmt.Println(1)\n")
+	if want := section.Text + "```go\nfmt.Println(1)\n```\n\n"; code.Text != want {
+		t.Fatalf("AppendCode() = %q, want %q", code.Text, want)
+	}
+	table := code.AppendTable([]string{"指标", "值"}, [][]string{{"QPS", "100"}})
+	if want := code.Text + "| 指标 | 值 |\n| --- | --- |\n| QPS | 100 |\n\n"; table.Text != want {
+		t.Fatalf("AppendTable() = %q, want %q", table.Text, want)
+	}
+	for _, m := range []Markdown{section, code, table} {
+		if m.Title != base.Title {
+			t.Fatalf("Title = %q, want %q", m.Title, base.Title)
+		}
+	}
+
+	want, err := os.ReadFile("testdata/markdown.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Text != string(want) {
+		t.Fatalf("built markdown = %q, want testdata/markdown.md %q", table.Text, want)
+	}
+}
+
+func TestMarkdownRaw(t *testing.T) {
+	base := Markdown{Title: "告警", Text: "正文"}
+	tests := []struct {
+		name string
+		got  Markdown
+		want string
+	}{
+		{"AppendRaw", base.AppendRaw("\n\n---"), "正文\n\n---"},
+		{"Prepend", base.Prepend("# 标题\n\n"), "# 标题\n\n正文"},
+		{"Wrap", base.Wrap("> ", "\n\n页脚"), "> 正文\n\n页脚"},
+		{"chained", base.Prepend("[").AppendRaw("]").Wrap("(", ")"), "([正文])"},
+		{"empty", Markdown{Title: "告警"}.Wrap("a", "b"), "ab"},
+	}
+	for _, tt := range tests {
+		if tt.got.Text != tt.want {
+			t.Errorf("%s: Text = %q, want %q", tt.name, tt.got.Text, tt.want)
+		}
+		if tt.got.Title != "告警" {
+			t.Errorf("%s: Title = %q, want unchanged", tt.name, tt.got.Title)
+		}
+	}
+	if base.Text != "正文" {
+		t.Fatalf("receiver modified: %q", base.Text)
+	}
+}
+
+func TestMarkdownSanitizer(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"[link]", `\[link\]`},
+		{"`code`", "\\`code\\`"},
+		{"*bold*", `\*bold\*`},
+		{"snake_case", `snake\_case`},
+		{"a|b", `a\|b`},
+		{"# title", `\# title`},
+		{"> quote", `\> quote`},
+		{"  # indented", `  \# indented`},
+		{"issue #1 > 0", "issue #1 > 0"},
+		{"line\n# next", "line\n\\# next"},
+		{`already \[escaped\]`, `already \[escaped\]`},
+		{`\\[`, `\\\[`},
+		{"普通文本", "普通文本"},
+	}
+	for _, tt := range tests {
+		if got := MarkdownSanitizer(tt.in); got != tt.want {
+			t.Errorf("MarkdownSanitizer(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	in, err := os.ReadFile("testdata/sanitize.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/sanitize.golden.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := MarkdownSanitizer(string(in)); got != string(want) {
+		t.Fatalf("MarkdownSanitizer(testdata/sanitize.md) = %q, want %q", got, want)
+	}
+}
+
+func TestAutoSanitize(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.AutoSanitize = true
+
+	if err := bot.SendMarkdown("[告警]", "*磁盘* 已满"); err != nil {
+		t.Fatal(err)
+	}
+	md := srv.Last(t).JSON(t)["markdown"].(map[string]any)
+	if md["title"] != `\[告警\]` || md["text"] != `\*磁盘\* 已满` {
+		t.Fatalf("markdown = %v, want sanitized title and text", md)
+	}
+	if err := bot.SendText("a_b"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != `a\_b` {
+		t.Fatalf("content = %v, want a\\_b", got)
+	}
+	if err := bot.SendLink("[t]", "*x*", "https://example.com", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["link"].(map[string]any)["title"]; got != "[t]" {
+		t.Fatalf("link title = %v, want link messages left unchanged", got)
+	}
+}
+
+func TestAutoSanitizeWrapperKeyword(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().WrapMsg(prefixText("# [告警] "))
+	bot.AutoSanitize = true
+	bot.Keywords = []string{"[告警]"}
+	bot.LogKeywordInjections = true
+
+	// 转换函数添加的标记和关键词不会被转义，因此也不会再次添加关键词
+	if err := bot.SendText("*磁盘* 已满"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != `# [告警] \*磁盘\* 已满` {
+		t.Fatalf("content = %v, want the wrapper markup kept and the caller text sanitized", got)
+	}
+	if len(bot.KeywordLog) != 0 {
+		t.Fatalf("KeywordLog = %v, want no injection", bot.KeywordLog)
+	}
+
+	// 关键词仍然原样添加在转义后的文本末尾
+	bot = srv.Bot()
+	bot.AutoSanitize = true
+	bot.Keywords = []string{"#告警"}
+	if err := bot.SendText("[db] 已满"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != `\[db\] 已满#告警` {
+		t.Fatalf("content = %v, want the keyword appended once unescaped", got)
+	}
+}
@@ -0,0 +1,68 @@
+package dingtalk
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// BotMetrics 机器人的发送统计，仅统计实际发出的请求，通过 Bot.Metrics 获取
+type BotMetrics struct {
+	sent    atomic.Int64
+	failed  atomic.Int64
+	latency atomic.Int64
+}
+
+// MetricsSnapshot 发送统计的快照
+type MetricsSnapshot struct {
+	// 发送成功的请求数
+	Sent int64
+
+	// 发送失败的请求数
+	Failed int64
+
+	// 所有请求的总耗时
+	TotalLatency time.Duration
+}
+
+// AverageLatency 返回请求的平均耗时，没有请求时返回零
+func (s MetricsSnapshot) AverageLatency() time.Duration {
+	total := s.Sent + s.Failed
+	if total == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(total)
+}
+
+// record 记录一次请求
+func (m *BotMetrics) record(latency time.Duration, err error) {
+	if err != nil {
+		m.failed.Add(1)
+	} else {
+		m.sent.Add(1)
+	}
+	m.latency.Add(int64(latency))
+}
+
+// Snapshot 返回当前统计的快照
+func (m *BotMetrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Sent:         m.sent.Load(),
+		Failed:       m.failed.Load(),
+		TotalLatency: time.Duration(m.latency.Load()),
+	}
+}
+
+// Reset 将所有统计清零，不影响机器人的其他状态
+func (m *BotMetrics) Reset() {
+	m.sent.Store(0)
+	m.failed.Store(0)
+	m.latency.Store(0)
+}
+
+// Metrics 返回机器人的发送统计，机器人为空时返回空
+func (b *Bot) Metrics() *BotMetrics {
+	if b == nil {
+		return nil
+	}
+	return &b.metrics
+}
@@ -0,0 +1,38 @@
+package dingtalk
+
+import "testing"
+
+func TestBotMetrics(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	metrics := bot.Metrics()
+	if metrics != bot.Metrics() {
+		t.Fatal("Metrics() returned different pointers")
+	}
+
+	bot.SendText("ok")
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	bot.SendText("failed")
+	if s := metrics.Snapshot(); s.Sent != 1 || s.Failed != 1 || s.TotalLatency <= 0 {
+		t.Fatalf("Snapshot() = %+v, want 1 sent and 1 failed", s)
+	}
+
+	metrics.Reset()
+	if s := metrics.Snapshot(); s != (MetricsSnapshot{}) {
+		t.Fatalf("Snapshot() after Reset = %+v, want zero", s)
+	}
+	if got := metrics.Snapshot().AverageLatency(); got != 0 {
+		t.Fatalf("AverageLatency() after Reset = %v, want 0", got)
+	}
+
+	srv.SetHandler(nil)
+	if err := bot.SendText("after reset"); err != nil {
+		t.Fatalf("SendText() after Reset = %v", err)
+	}
+	if s := metrics.Snapshot(); s.Sent != 1 || s.Failed != 0 {
+		t.Fatalf("Snapshot() after send = %+v, want 1 sent", s)
+	}
+	if (*Bot)(nil).Metrics() != nil {
+		t.Fatal("nil bot Metrics() != nil")
+	}
+}
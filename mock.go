@@ -0,0 +1,59 @@
+package dingtalk
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Drelf2018/req"
+)
+
+// 测试及开发环境使用的占位凭证和安全密钥，凭证为 TestWebhookToken 的机器人不会向钉钉发送请求
+const (
+	TestWebhookToken  = "dingtalk-test-token"
+	TestWebhookSecret = "SECdingtalk-test-secret"
+)
+
+// mockTraceID 模拟响应中的链路标识
+const mockTraceID = "dingtalk-mock-trace-id"
+
+// mockTransport 在进程内模拟钉钉接口，总是返回发送成功
+type mockTransport struct{}
+
+func (mockTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		io.Copy(io.Discard, r.Body)
+		r.Body.Close()
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(TraceIDHeader, mockTraceID)
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(`{"errcode":0,"errmsg":"ok"}`)),
+		Request:    r,
+	}, nil
+}
+
+var _ http.RoundTripper = mockTransport{}
+
+// mockSession 通过 mockTransport 发送请求的会话
+var mockSession = &req.Session{
+	Client: http.Client{Transport: mockTransport{}},
+	Header: req.DefaultSession.Header.Clone(),
+}
+
+// IsTest 检测机器人凭证是否为 TestWebhookToken ，此时发送消息会由进程内的模拟接口处理
+func (b *Bot) IsTest() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Token == TestWebhookToken
+}
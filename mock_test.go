@@ -0,0 +1,51 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// failTransport 收到请求时使测试失败，用于确认没有发出真实请求
+type failTransport struct{ t testing.TB }
+
+func (f failTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected real request to %s", r.URL)
+	return nil, errors.New("real request")
+}
+
+func TestIsTest(t *testing.T) {
+	if !(&Bot{Token: TestWebhookToken}).IsTest() {
+		t.Error("IsTest() = false for TestWebhookToken")
+	}
+	if (&Bot{Token: testToken}).IsTest() {
+		t.Error("IsTest() = true for real token")
+	}
+	if (*Bot)(nil).IsTest() {
+		t.Error("IsTest() = true for nil bot")
+	}
+}
+
+func TestMockRouting(t *testing.T) {
+	bot := (&Bot{Token: TestWebhookToken, Secret: TestWebhookSecret}).EnableHistory(2)
+	bot.SetHTTPClient(&http.Client{Transport: failTransport{t}})
+	for _, msg := range []Msg{Text{Content: "text"}, Markdown{Title: "title", Text: "text"}} {
+		if err := bot.SendWithContext(context.Background(), msg); err != nil {
+			t.Fatalf("SendWithContext(%T) = %v", msg, err)
+		}
+	}
+	for i, record := range bot.SentHistory(0) {
+		if record.TraceID != mockTraceID {
+			t.Errorf("record %d TraceID = %q, want %q", i, record.TraceID, mockTraceID)
+		}
+	}
+}
+
+func TestMockRoutingResolvedToken(t *testing.T) {
+	bot := &Bot{TokenResolver: func(context.Context) (string, error) { return TestWebhookToken, nil }}
+	bot.SetHTTPClient(&http.Client{Transport: failTransport{t}})
+	if err := bot.SendText("resolved"); err != nil {
+		t.Fatalf("SendText() = %v", err)
+	}
+}
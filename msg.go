@@ -1,5 +1,11 @@
 package dingtalk
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
 // MsgType 表示消息类型的字符串，已内置五种类型
 //
 //	MsgText       // 文本类型
@@ -17,11 +23,21 @@ const (
 	MsgFeedCard   MsgType = "feedCard"   // FeedCard 类型，不支持@人
 )
 
+// AtSupported 检测该类型的消息是否支持@人，链接类型和 FeedCard 类型不支持
+func (t MsgType) AtSupported() bool {
+	return t != MsgLink && t != MsgFeedCard
+}
+
 // Msg 消息接口
 type Msg interface {
 	Type() MsgType
 }
 
+// Equaler 可逐字段比较是否相同的消息，消息去重时会优先使用
+type Equaler interface {
+	Equal(other Msg) bool
+}
+
 // Text 文本类型消息
 type Text struct {
 	Content string `json:"content" yaml:"content" toml:"content" long:"content"` // 文本消息的内容
@@ -33,6 +49,13 @@ func (Text) Type() MsgType {
 
 var _ Msg = Text{}
 
+func (t Text) Equal(other Msg) bool {
+	o, ok := other.(Text)
+	return ok && t == o
+}
+
+var _ Equaler = Text{}
+
 // Link 链接类型消息
 type Link struct {
 	// 链接消息标题
@@ -54,6 +77,13 @@ func (Link) Type() MsgType {
 
 var _ Msg = Link{}
 
+func (l Link) Equal(other Msg) bool {
+	o, ok := other.(Link)
+	return ok && l == o
+}
+
+var _ Equaler = Link{}
+
 // Markdown markdown 类型消息
 type Markdown struct {
 	// 消息会话列表中展示的标题，非消息体的标题
@@ -69,6 +99,13 @@ func (Markdown) Type() MsgType {
 
 var _ Msg = Markdown{}
 
+func (m Markdown) Equal(other Msg) bool {
+	o, ok := other.(Markdown)
+	return ok && m == o
+}
+
+var _ Equaler = Markdown{}
+
 // ActionCard 整体跳转 actionCard 类型消息
 type ActionCard struct {
 	// 消息会话列表中展示的标题，非消息体的标题
@@ -82,6 +119,9 @@ type ActionCard struct {
 
 	// 点击 singleTitle 按钮触发的 URL
 	SingleURL string `json:"singleURL,omitempty" yaml:"singleURL" toml:"singleURL" long:"singleURL"`
+
+	// 是否隐藏发送者头像，0：显示，1：隐藏，为空时钉钉视为显示头像
+	HideAvatar string `json:"hideAvatar,omitempty" yaml:"hideAvatar" toml:"hideAvatar" long:"hideAvatar"`
 }
 
 func (ActionCard) Type() MsgType {
@@ -90,6 +130,53 @@ func (ActionCard) Type() MsgType {
 
 var _ Msg = ActionCard{}
 
+func (a ActionCard) Equal(other Msg) bool {
+	o, ok := other.(ActionCard)
+	return ok && a == o
+}
+
+var _ Equaler = ActionCard{}
+
+// SetSingleButton 设置整体跳转按钮，返回新的消息
+func (a ActionCard) SetSingleButton(title, url string) ActionCard {
+	a.SingleTitle = title
+	a.SingleURL = url
+	return a
+}
+
+// AddButton 添加一个独立跳转按钮，返回不含整体跳转按钮的独立跳转消息
+func (a ActionCard) AddButton(title, url string) ActionsCard {
+	return ActionsCard{Title: a.Title, Text: a.Text, HideAvatar: a.HideAvatar}.AddButton(title, url)
+}
+
+// hideAvatar 将是否隐藏头像转换为钉钉使用的字符串
+func hideAvatar(hide bool) string {
+	if hide {
+		return "1"
+	}
+	return "0"
+}
+
+// WithHideAvatar 设置是否隐藏发送者头像，返回新的消息
+func (a ActionCard) WithHideAvatar(hide bool) ActionCard {
+	a.HideAvatar = hideAvatar(hide)
+	return a
+}
+
+// IsMultiButton 整体跳转消息总是单按钮模式
+func (ActionCard) IsMultiButton() bool {
+	return false
+}
+
+// ToActionsCard 将整体跳转按钮转换为只有一个按钮的独立跳转消息，没有整体跳转按钮时按钮列表为空
+func (a ActionCard) ToActionsCard() ActionsCard {
+	c := ActionsCard{Title: a.Title, Text: a.Text, HideAvatar: a.HideAvatar}
+	if a.SingleTitle == "" && a.SingleURL == "" {
+		return c
+	}
+	return c.AddButton(a.SingleTitle, a.SingleURL)
+}
+
 // ActionCardBtn actionCard 类型消息的按钮
 type ActionCardBtn struct {
 	// 按钮上显示的文本
@@ -99,6 +186,27 @@ type ActionCardBtn struct {
 	ActionURL string `json:"actionURL" yaml:"actionURL" toml:"actionURL" long:"actionURL"`
 }
 
+// BtnOrientation actionCard 类型消息内按钮排列方式
+type BtnOrientation string
+
+const (
+	BtnOrientationVertical   BtnOrientation = "0" // 按钮竖直排列
+	BtnOrientationHorizontal BtnOrientation = "1" // 按钮横向排列
+)
+
+// ErrInvalidBtnOrientation 无效的按钮排列方式
+var ErrInvalidBtnOrientation = errors.New("dingtalk: invalid btn orientation")
+
+// Validate 检测按钮排列方式是否为内置的两种之一
+func (o BtnOrientation) Validate() error {
+	switch o {
+	case BtnOrientationVertical, BtnOrientationHorizontal:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidBtnOrientation, string(o))
+	}
+}
+
 // ActionsCard 独立跳转 actionCard 类型消息
 type ActionsCard struct {
 	// 消息会话列表中展示的标题，非消息体的标题
@@ -111,7 +219,10 @@ type ActionsCard struct {
 	Btns []ActionCardBtn `json:"btns,omitempty" yaml:"btns" toml:"btns" long:"btns"`
 
 	// 消息内按钮排列方式，0：按钮竖直排列，1：按钮横向排列
-	BtnOrientation string `json:"btnOrientation,omitempty" yaml:"btnOrientation" toml:"btnOrientation" long:"btnOrientation"`
+	BtnOrientation BtnOrientation `json:"btnOrientation,omitempty" yaml:"btnOrientation" toml:"btnOrientation" long:"btnOrientation"`
+
+	// 是否隐藏发送者头像，0：显示，1：隐藏，为空时钉钉视为显示头像
+	HideAvatar string `json:"hideAvatar,omitempty" yaml:"hideAvatar" toml:"hideAvatar" long:"hideAvatar"`
 }
 
 func (ActionsCard) Type() MsgType {
@@ -120,6 +231,52 @@ func (ActionsCard) Type() MsgType {
 
 var _ Msg = ActionsCard{}
 
+func (a ActionsCard) Equal(other Msg) bool {
+	o, ok := other.(ActionsCard)
+	return ok && a.Title == o.Title && a.Text == o.Text && a.BtnOrientation == o.BtnOrientation && a.HideAvatar == o.HideAvatar && slices.Equal(a.Btns, o.Btns)
+}
+
+var _ Equaler = ActionsCard{}
+
+// AddButton 在按钮列表末尾添加一个按钮，返回新的消息
+func (a ActionsCard) AddButton(title, url string) ActionsCard {
+	a.Btns = append(slices.Clip(a.Btns), ActionCardBtn{Title: title, ActionURL: url})
+	return a
+}
+
+// SetSingleButton 设置整体跳转按钮，返回不含按钮列表的整体跳转消息
+func (a ActionsCard) SetSingleButton(title, url string) ActionCard {
+	return ActionCard{Title: a.Title, Text: a.Text, SingleTitle: title, SingleURL: url, HideAvatar: a.HideAvatar}
+}
+
+// WithHideAvatar 设置是否隐藏发送者头像，返回新的消息
+func (a ActionsCard) WithHideAvatar(hide bool) ActionsCard {
+	a.HideAvatar = hideAvatar(hide)
+	return a
+}
+
+// WithBtnOrientation 设置按钮排列方式，返回新的消息
+func (a ActionsCard) WithBtnOrientation(o BtnOrientation) ActionsCard {
+	a.BtnOrientation = o
+	return a
+}
+
+// IsMultiButton 检测消息是否包含按钮列表
+func (a ActionsCard) IsMultiButton() bool {
+	return len(a.Btns) > 0
+}
+
+// ErrMultipleButtons 独立跳转消息的按钮数量不为一，无法转换为整体跳转消息
+var ErrMultipleButtons = errors.New("dingtalk: actions card must have exactly one btn")
+
+// ToActionCard 将只有一个按钮的独立跳转消息转换为整体跳转消息，按钮数量不为一时返回 ErrMultipleButtons
+func (a ActionsCard) ToActionCard() (ActionCard, error) {
+	if len(a.Btns) != 1 {
+		return ActionCard{}, fmt.Errorf("%w: got %d", ErrMultipleButtons, len(a.Btns))
+	}
+	return a.SetSingleButton(a.Btns[0].Title, a.Btns[0].ActionURL), nil
+}
+
 // FeedCardLink feedCard 类型消息的内容
 type FeedCardLink struct {
 	// 每条内容的标题
@@ -143,3 +300,77 @@ func (FeedCard) Type() MsgType {
 }
 
 var _ Msg = FeedCard{}
+
+func (f FeedCard) Equal(other Msg) bool {
+	o, ok := other.(FeedCard)
+	return ok && slices.Equal(f.Links, o.Links)
+}
+
+var _ Equaler = FeedCard{}
+
+// Append 返回追加了内容的新 feedCard 类型消息，不会修改原消息
+func (f FeedCard) Append(links ...FeedCardLink) FeedCard {
+	return FeedCard{Links: append(append(make([]FeedCardLink, 0, len(f.Links)+len(links)), f.Links...), links...)}
+}
+
+// Slice 返回内容为 Links[i:j] 拷贝的新 feedCard 类型消息，越界时与切片一样引发恐慌
+func (f FeedCard) Slice(i, j int) FeedCard {
+	return FeedCard{Links: append([]FeedCardLink(nil), f.Links[i:j]...)}
+}
+
+// PageCount 返回按每页 pageSize 条内容分页后的页数，pageSize 不为正时引发恐慌
+func (f FeedCard) PageCount(pageSize int) int {
+	if pageSize <= 0 {
+		panic(fmt.Sprintf("dingtalk: non-positive feed card page size %d", pageSize))
+	}
+	return (len(f.Links) + pageSize - 1) / pageSize
+}
+
+// Page 返回按每页 pageSize 条内容分页后第 page 页（从零开始）的新 feedCard 类型消息，
+// 内容为空时返回空消息，页码越界时与切片一样引发恐慌
+func (f FeedCard) Page(pageSize, page int) FeedCard {
+	count := f.PageCount(pageSize)
+	if len(f.Links) == 0 {
+		return FeedCard{}
+	}
+	if page < 0 || page >= count {
+		panic(fmt.Sprintf("dingtalk: feed card page %d out of range [0:%d]", page, count))
+	}
+	start := page * pageSize
+	return f.Slice(start, min(start+pageSize, len(f.Links)))
+}
+
+// Filter 返回仅包含满足条件内容的新 feedCard 类型消息，不会修改原消息
+func (f FeedCard) Filter(predicate func(FeedCardLink) bool) FeedCard {
+	links := make([]FeedCardLink, 0, len(f.Links))
+	for _, link := range f.Links {
+		if predicate(link) {
+			links = append(links, link)
+		}
+	}
+	return FeedCard{Links: links}
+}
+
+// ErrEmptyFeedCard feedCard 类型消息没有任何内容
+var ErrEmptyFeedCard = errors.New("dingtalk: empty feed card")
+
+// BuildFeedCard 使用等长的标题和跳转链接切片构建 feedCard 类型消息，图片切片较短时缺少的图片 URL 为空
+func BuildFeedCard(titles, urls, pics []string) (FeedCard, error) {
+	if len(titles) == 0 {
+		return FeedCard{}, ErrEmptyFeedCard
+	}
+	if len(urls) != len(titles) {
+		return FeedCard{}, fmt.Errorf("dingtalk: urls length %d does not match titles length %d", len(urls), len(titles))
+	}
+	if len(pics) > len(titles) {
+		return FeedCard{}, fmt.Errorf("dingtalk: pics length %d exceeds titles length %d", len(pics), len(titles))
+	}
+	links := make([]FeedCardLink, len(titles))
+	for i, title := range titles {
+		links[i] = FeedCardLink{Title: title, MessageURL: urls[i]}
+		if i < len(pics) {
+			links[i].PicURL = pics[i]
+		}
+	}
+	return FeedCard{Links: links}, nil
+}
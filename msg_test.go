@@ -0,0 +1,388 @@
+package dingtalk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// feedLinks 生成 n 条 feedCard 类型消息的内容
+func feedLinks(n int) []FeedCardLink {
+	links := make([]FeedCardLink, n)
+	for i := range links {
+		links[i] = FeedCardLink{Title: fmt.Sprintf("title%d", i), MessageURL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	return links
+}
+
+// mustPanic 检测函数是否引发恐慌
+func mustPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("%s did not panic", name)
+		}
+	}()
+	fn()
+}
+
+func TestFeedCardAppend(t *testing.T) {
+	links := feedLinks(10)
+	f := FeedCard{Links: links[:6:6]}
+	g := f.Append(links[6:]...)
+	if len(g.Links) != 10 || !reflect.DeepEqual(g.Links, links) {
+		t.Fatalf("Append() = %d links, want 10", len(g.Links))
+	}
+	if len(f.Links) != 6 {
+		t.Fatalf("Append() modified the receiver: %d links", len(f.Links))
+	}
+	g.Links[0].Title = "changed"
+	if f.Links[0].Title != "title0" {
+		t.Fatal("Append() result shares the receiver's backing array")
+	}
+}
+
+func TestFeedCardSlice(t *testing.T) {
+	f := FeedCard{Links: feedLinks(5)}
+	s := f.Slice(1, 3)
+	if !reflect.DeepEqual(s.Links, f.Links[1:3]) {
+		t.Fatalf("Slice(1, 3) = %v", s.Links)
+	}
+	s.Links[0].Title = "changed"
+	if f.Links[1].Title != "title1" {
+		t.Fatal("Slice() result shares the receiver's backing array")
+	}
+	mustPanic(t, "Slice(0, 6)", func() { f.Slice(0, 6) })
+	mustPanic(t, "Slice(3, 2)", func() { f.Slice(3, 2) })
+}
+
+func TestFeedCardPage(t *testing.T) {
+	tests := []struct {
+		links, size, count int
+		last               int
+	}{
+		{6, 3, 2, 3},
+		{7, 3, 3, 1},
+		{2, 5, 1, 2},
+	}
+	for _, tt := range tests {
+		f := FeedCard{Links: feedLinks(tt.links)}
+		if got := f.PageCount(tt.size); got != tt.count {
+			t.Fatalf("PageCount(%d) with %d links = %d, want %d", tt.size, tt.links, got, tt.count)
+		}
+		var joined []FeedCardLink
+		for page := 0; page < tt.count; page++ {
+			joined = append(joined, f.Page(tt.size, page).Links...)
+		}
+		if !reflect.DeepEqual(joined, f.Links) {
+			t.Fatalf("pages of %d links = %v", tt.links, joined)
+		}
+		if got := len(f.Page(tt.size, tt.count-1).Links); got != tt.last {
+			t.Fatalf("last page of %d links has %d links, want %d", tt.links, got, tt.last)
+		}
+		mustPanic(t, fmt.Sprintf("Page(%d, %d)", tt.size, tt.count), func() { f.Page(tt.size, tt.count) })
+		mustPanic(t, fmt.Sprintf("Page(%d, -1)", tt.size), func() { f.Page(tt.size, -1) })
+	}
+
+	empty := FeedCard{}
+	if empty.PageCount(3) != 0 || len(empty.Page(3, 0).Links) != 0 || len(empty.Page(3, 5).Links) != 0 {
+		t.Fatal("empty FeedCard should have no pages and return empty pages")
+	}
+	mustPanic(t, "PageCount(0)", func() { empty.PageCount(0) })
+}
+
+func TestFeedCardFilter(t *testing.T) {
+	f := FeedCard{Links: feedLinks(4)}
+	even := f.Filter(func(l FeedCardLink) bool { return l.Title == "title0" || l.Title == "title2" })
+	if len(even.Links) != 2 || even.Links[1].Title != "title2" {
+		t.Fatalf("Filter() = %v", even.Links)
+	}
+	none := f.Filter(func(FeedCardLink) bool { return false })
+	if len(none.Links) != 0 {
+		t.Fatalf("Filter() = %v, want empty", none.Links)
+	}
+	if len(f.Links) != 4 {
+		t.Fatal("Filter() modified the receiver")
+	}
+}
+
+func TestBtnOrientationValidate(t *testing.T) {
+	// 排列方式是独立的类型，无法直接赋值为字符串变量
+	var o BtnOrientation = BtnOrientationHorizontal
+	card := ActionsCard{Title: "t", Text: "t"}.AddButton("a", "https://example.com").WithBtnOrientation(o)
+	if err := card.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, o := range []BtnOrientation{BtnOrientationVertical, BtnOrientationHorizontal} {
+		if err := o.Validate(); err != nil {
+			t.Errorf("%q.Validate() error = %v", o, err)
+		}
+	}
+	for _, o := range []BtnOrientation{"Horizontal", "HORIZONTAL", "2", ""} {
+		if err := o.Validate(); !errors.Is(err, ErrInvalidBtnOrientation) {
+			t.Errorf("%q.Validate() error = %v, want ErrInvalidBtnOrientation", o, err)
+		}
+	}
+	card.BtnOrientation = "horizontal"
+	if err := card.Validate(); !errors.Is(err, ErrInvalidBtnOrientation) {
+		t.Fatalf("ActionsCard.Validate() error = %v, want ErrInvalidBtnOrientation", err)
+	}
+}
+
+func TestMsgEqual(t *testing.T) {
+	if !(Text{Content: "hello"}).Equal(Text{Content: "hello"}) {
+		t.Fatal(`Text{"hello"} should equal Text{"hello"}`)
+	}
+	if (Text{Content: "hello"}).Equal(Text{Content: "world"}) {
+		t.Fatal(`Text{"hello"} should differ from Text{"world"}`)
+	}
+	if (Text{Content: "hello"}).Equal(Markdown{Title: "hello", Text: "hello"}) {
+		t.Fatal("messages of different types should differ")
+	}
+	btns := ActionsCard{Title: "t", Text: "x"}.AddButton("a", "https://example.com/a")
+	links := FeedCard{Links: feedLinks(3)}
+	for _, c := range []struct {
+		a, b  Equaler
+		other Msg
+	}{
+		{Link{Title: "t", MessageURL: "u"}, Link{Title: "t", MessageURL: "u"}, Link{Title: "t", MessageURL: "v"}},
+		{Markdown{Title: "t", Text: "x"}, Markdown{Title: "t", Text: "x"}, Markdown{Title: "t", Text: "y"}},
+		{ActionCard{Title: "t", SingleURL: "u"}, ActionCard{Title: "t", SingleURL: "u"}, ActionCard{Title: "t", SingleURL: "u", HideAvatar: "1"}},
+		{btns, ActionsCard{Title: "t", Text: "x", Btns: []ActionCardBtn{{Title: "a", ActionURL: "https://example.com/a"}}}, btns.AddButton("b", "https://example.com/b")},
+		{links, links.Slice(0, 3), links.Slice(0, 2)},
+	} {
+		if !c.a.Equal(c.b.(Msg)) {
+			t.Errorf("%#v should equal %#v", c.a, c.b)
+		}
+		if c.a.Equal(c.other) {
+			t.Errorf("%#v should differ from %#v", c.a, c.other)
+		}
+	}
+}
+
+func TestActionCardButtons(t *testing.T) {
+	single := ActionCard{Title: "title", Text: "text"}.SetSingleButton("查看", "https://example.com")
+	if single.IsMultiButton() {
+		t.Fatal("ActionCard.IsMultiButton() = true")
+	}
+
+	multi := single.AddButton("同意", "https://example.com/yes")
+	if !multi.IsMultiButton() || len(multi.Btns) != 1 {
+		t.Fatalf("AddButton() = %+v, want one button", multi)
+	}
+	if multi.Title != single.Title || multi.Text != single.Text {
+		t.Fatalf("AddButton() = %+v, want title and text kept", multi)
+	}
+
+	first := multi.AddButton("拒绝", "https://example.com/no")
+	second := multi.AddButton("忽略", "https://example.com/skip")
+	if len(multi.Btns) != 1 || first.Btns[1].Title != "拒绝" || second.Btns[1].Title != "忽略" {
+		t.Fatalf("AddButton() shares buttons between values: %+v %+v", first.Btns, second.Btns)
+	}
+
+	back := first.SetSingleButton("查看全部", "https://example.com/all")
+	if back.IsMultiButton() || back.SingleTitle != "查看全部" || back.SingleURL != "https://example.com/all" {
+		t.Fatalf("SetSingleButton() = %+v", back)
+	}
+	if (ActionsCard{}).IsMultiButton() {
+		t.Fatal("empty ActionsCard.IsMultiButton() = true")
+	}
+}
+
+func TestSendFeedCardFromURLs(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	titles := []string{"a", "b", "c"}
+	urls := []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}
+
+	tests := []struct {
+		name string
+		pics []string
+		want []string
+	}{
+		{"normal", []string{"https://img/a", "https://img/b", "https://img/c"}, []string{"https://img/a", "https://img/b", "https://img/c"}},
+		{"short pics", []string{"https://img/a"}, []string{"https://img/a", "", ""}},
+		{"empty pics", nil, []string{"", "", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := bot.SendFeedCardFromURLs(context.Background(), titles, urls, tt.pics); err != nil {
+				t.Fatalf("SendFeedCardFromURLs() = %v", err)
+			}
+			body := srv.Last(t).JSON(t)
+			links := body["feedCard"].(map[string]any)["links"].([]any)
+			if len(links) != len(titles) {
+				t.Fatalf("got %d links, want %d", len(links), len(titles))
+			}
+			for i, l := range links {
+				link := l.(map[string]any)
+				if link["title"] != titles[i] || link["messageURL"] != urls[i] || link["picURL"] != tt.want[i] {
+					t.Errorf("links[%d] = %v", i, link)
+				}
+			}
+		})
+	}
+
+	before := len(srv.Requests())
+	if err := bot.SendFeedCardFromURLs(context.Background(), nil, nil, nil); !errors.Is(err, ErrEmptyFeedCard) {
+		t.Fatalf("SendFeedCardFromURLs(nil) = %v, want ErrEmptyFeedCard", err)
+	}
+	if err := bot.SendFeedCardFromURLs(context.Background(), titles, urls[:1], nil); err == nil {
+		t.Fatal("SendFeedCardFromURLs() with mismatched urls = nil, want error")
+	}
+	if n := len(srv.Requests()); n != before {
+		t.Fatalf("invalid inputs sent %d requests", n-before)
+	}
+}
+
+func TestSendActionPairs(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Keywords = []string{"告警"}
+
+	tests := []struct {
+		name        string
+		send        func(ctx context.Context, title, text string, pairs ...string) error
+		orientation string
+	}{
+		{"vertical", bot.SendActionPairs, "0"},
+		{"horizontal", bot.SendActionPairsH, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.send(context.Background(), "标题", "正文", "同意", "https://example.com/yes", "拒绝", "https://example.com/no")
+			if err != nil {
+				t.Fatalf("send = %v", err)
+			}
+			card := srv.Last(t).JSON(t)["actionCard"].(map[string]any)
+			if card["btnOrientation"] != tt.orientation {
+				t.Errorf("btnOrientation = %v, want %s", card["btnOrientation"], tt.orientation)
+			}
+			if card["text"] != "正文告警" {
+				t.Errorf("text = %v, want keyword injected", card["text"])
+			}
+			want := []any{
+				map[string]any{"title": "同意", "actionURL": "https://example.com/yes"},
+				map[string]any{"title": "拒绝", "actionURL": "https://example.com/no"},
+			}
+			if !reflect.DeepEqual(card["btns"], want) {
+				t.Errorf("btns = %v, want %v", card["btns"], want)
+			}
+		})
+	}
+
+	before := len(srv.Requests())
+	for _, send := range []func(ctx context.Context, title, text string, pairs ...string) error{bot.SendActionPairs, bot.SendActionPairsH} {
+		if err := send(context.Background(), "t", "t", "only title"); !errors.Is(err, ErrOddPairCount) {
+			t.Fatalf("send with odd pairs = %v, want ErrOddPairCount", err)
+		}
+	}
+	if n := len(srv.Requests()); n != before {
+		t.Fatalf("odd pairs sent %d requests", n-before)
+	}
+}
+
+func TestMsgTypeAtSupported(t *testing.T) {
+	for typ, want := range map[MsgType]bool{MsgText: true, MsgMarkdown: true, MsgActionCard: true, MsgLink: false, MsgFeedCard: false} {
+		if got := typ.AtSupported(); got != want {
+			t.Errorf("%s.AtSupported() = %v, want %v", typ, got, want)
+		}
+	}
+}
+
+func TestSendIfAtAllowed(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	link := Link{Title: "t", Text: "t", MessageURL: "https://example.com"}
+	at := At{AtMobiles: []string{"13800000000"}}
+
+	if err := bot.Send(link, AtMobile("13800000000")); !errors.Is(err, ErrAtNotSupported) {
+		t.Fatalf("Send(Link) with At = %v, want ErrAtNotSupported", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("rejected send reached the server %d times", n)
+	}
+
+	if err := bot.SendIfAtAllowed(context.Background(), link, at); err != nil {
+		t.Fatalf("SendIfAtAllowed(Link) = %v", err)
+	}
+	if _, ok := srv.Last(t).JSON(t)["at"]; ok {
+		t.Fatal("SendIfAtAllowed(Link) sent at")
+	}
+
+	if err := bot.SendIfAtAllowed(context.Background(), Text{Content: "t"}, at); err != nil {
+		t.Fatalf("SendIfAtAllowed(Text) = %v", err)
+	}
+	got, _ := srv.Last(t).JSON(t)["at"].(map[string]any)
+	if mobiles, _ := got["atMobiles"].([]any); len(mobiles) != 1 || mobiles[0] != "13800000000" {
+		t.Fatalf("SendIfAtAllowed(Text) at = %v", got)
+	}
+}
+
+func TestHideAvatarJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  any
+		want string
+	}{
+		{"zero", ActionCard{}, ""},
+		{"show", ActionCard{}.WithHideAvatar(false), `"hideAvatar":"0"`},
+		{"hide", ActionCard{}.WithHideAvatar(true), `"hideAvatar":"1"`},
+		{"actions zero", ActionsCard{}, ""},
+		{"actions hide", ActionsCard{}.WithHideAvatar(true), `"hideAvatar":"1"`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want == "" {
+			if strings.Contains(string(data), "hideAvatar") {
+				t.Errorf("%s: %s contains hideAvatar", tt.name, data)
+			}
+		} else if !strings.Contains(string(data), tt.want) {
+			t.Errorf("%s: %s does not contain %s", tt.name, data, tt.want)
+		}
+	}
+
+	card := ActionsCard{}.WithBtnOrientation(BtnOrientationHorizontal)
+	if card.BtnOrientation != BtnOrientationHorizontal {
+		t.Fatalf("WithBtnOrientation() = %q", card.BtnOrientation)
+	}
+	if single := (ActionCard{}).WithHideAvatar(true).AddButton("a", "https://example.com"); single.HideAvatar != "1" {
+		t.Fatalf("AddButton() HideAvatar = %q, want kept", single.HideAvatar)
+	}
+}
+
+func TestActionCardConversion(t *testing.T) {
+	card := ActionCard{Title: "title", Text: "text"}.SetSingleButton("查看", "https://example.com").WithHideAvatar(true)
+
+	actions := card.ToActionsCard()
+	if len(actions.Btns) != 1 || actions.Btns[0] != (ActionCardBtn{Title: "查看", ActionURL: "https://example.com"}) {
+		t.Fatalf("ToActionsCard() buttons = %+v", actions.Btns)
+	}
+	back, err := actions.ToActionCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back != card {
+		t.Fatalf("round trip = %+v, want %+v", back, card)
+	}
+
+	if got := (ActionCard{Title: "title"}).ToActionsCard(); len(got.Btns) != 0 || got.Title != "title" {
+		t.Fatalf("ToActionsCard() without button = %+v", got)
+	}
+	for _, n := range []int{0, 2} {
+		c := ActionsCard{Title: "title", Text: "text"}
+		for i := 0; i < n; i++ {
+			c = c.AddButton(fmt.Sprint(i), "https://example.com")
+		}
+		if _, err := c.ToActionCard(); !errors.Is(err, ErrMultipleButtons) {
+			t.Fatalf("ToActionCard() with %d buttons = %v, want ErrMultipleButtons", n, err)
+		}
+	}
+}
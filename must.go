@@ -0,0 +1,22 @@
+package dingtalk
+
+// MustSend 发送消息，失败时使用该错误引发恐慌，仅应在初始化代码或测试中使用
+func (b *Bot) MustSend(msg Msg, handlers ...SendHandler) {
+	if err := b.Send(msg, handlers...); err != nil {
+		panic(err)
+	}
+}
+
+// MustSendText 发送文本类型消息，失败时使用该错误引发恐慌，仅应在初始化代码或测试中使用
+func (b *Bot) MustSendText(content string, handlers ...SendHandler) {
+	if err := b.SendText(content, handlers...); err != nil {
+		panic(err)
+	}
+}
+
+// MustSendMarkdown 发送 markdown 类型消息，失败时使用该错误引发恐慌，仅应在初始化代码或测试中使用
+func (b *Bot) MustSendMarkdown(title, text string, handlers ...SendHandler) {
+	if err := b.SendMarkdown(title, text, handlers...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,42 @@
+package dingtalk
+
+import (
+	"errors"
+	"testing"
+)
+
+// recoverError 调用函数并返回其引发恐慌时使用的错误
+func recoverError(fn func()) (err error) {
+	defer func() {
+		err, _ = recover().(error)
+	}()
+	fn()
+	return nil
+}
+
+func TestMustSend(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	calls := map[string]func(){
+		"MustSend":         func() { bot.MustSend(Text{Content: "must"}) },
+		"MustSendText":     func() { bot.MustSendText("must") },
+		"MustSendMarkdown": func() { bot.MustSendMarkdown("must", "must") },
+	}
+	for name, call := range calls {
+		if err := recoverError(call); err != nil {
+			t.Errorf("%s() panicked on success: %v", name, err)
+		}
+	}
+
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	for name, call := range calls {
+		err := recoverError(call)
+		var sendErr SendError
+		if !errors.Is(err, ErrInvalidToken) || !errors.As(err, &sendErr) {
+			t.Errorf("%s() panic = %v, want the original SendError", name, err)
+		}
+	}
+	if err := recoverError(func() { (*Bot)(nil).MustSendText("nil") }); !errors.Is(err, ErrNilBot) {
+		t.Fatalf("nil bot MustSendText() panic = %v, want ErrNilBot", err)
+	}
+}
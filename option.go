@@ -0,0 +1,173 @@
+package dingtalk
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// BotOption 机器人配置项
+type BotOption func(*Bot) error
+
+// NewBot 使用配置项创建机器人，并调用 Validate 检测配置
+func NewBot(opts ...BotOption) (*Bot, error) {
+	b := &Bot{}
+	for _, opt := range opts {
+		if err := opt(b); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// WithName 设置名称
+func WithName(name string) BotOption {
+	return func(b *Bot) error {
+		b.Name = name
+		return nil
+	}
+}
+
+// WithBotToken 设置调用接口的凭证，与为上下文设置凭证的 WithToken 不同，该配置项用于 NewBot
+func WithBotToken(token string) BotOption {
+	return func(b *Bot) error {
+		b.Token = token
+		return nil
+	}
+}
+
+// WithBotSecret 设置安全密钥，与为上下文设置安全密钥的 WithSecret 不同，该配置项用于 NewBot
+func WithBotSecret(secret string) BotOption {
+	return func(b *Bot) error {
+		b.Secret = secret
+		return nil
+	}
+}
+
+// WithKeywords 设置自定义关键词
+func WithKeywords(keywords []string) BotOption {
+	return func(b *Bot) error {
+		b.Keywords = append([]string(nil), keywords...)
+		return nil
+	}
+}
+
+// WithTimeout 设置全局请求超时时间
+func WithTimeout(timeout time.Duration) BotOption {
+	return func(b *Bot) error {
+		b.Timeout = timeout
+		return nil
+	}
+}
+
+// WithHTTPClient 设置发送请求使用的客户端，详见 SetHTTPClient
+func WithHTTPClient(client *http.Client) BotOption {
+	return func(b *Bot) error {
+		b.httpClient = client
+		return nil
+	}
+}
+
+// Clone 深拷贝机器人的配置，消息转换函数、 AddAfterSendHook 注册的钩子和 DebugAll 设置的调试输出也会被拷贝。
+// 限流、去重、熔断、发送记录等运行时状态按相同的配置重新创建，
+// KeywordLog 、发送统计、暂停状态、 Debug 注册的单次抓取、配置文件监听和关闭状态不会被拷贝
+func (b *Bot) Clone() *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	c := &Bot{
+		Name:                 b.Name,
+		Token:                b.Token,
+		Secret:               b.Secret,
+		Keywords:             append([]string(nil), b.Keywords...),
+		Timeout:              b.Timeout,
+		Limit:                b.Limit,
+		BaseURL:              b.BaseURL,
+		MaxContentLen:        b.MaxContentLen,
+		TokenResolver:        b.TokenResolver,
+		SecretResolver:       b.SecretResolver,
+		HistoryCap:           b.HistoryCap,
+		KeywordInjector:      b.KeywordInjector,
+		LogKeywordInjections: b.LogKeywordInjections,
+		SlogLogger:           b.SlogLogger,
+		Tracer:               b.Tracer,
+		InjectTraceInFooter:  b.InjectTraceInFooter,
+		PauseBehavior:        b.PauseBehavior,
+		traceSends:           b.traceSends,
+		Callbacks:            b.Callbacks,
+		OnBeforeSend:         b.OnBeforeSend,
+		OnAfterSend:          b.OnAfterSend,
+		Interceptor:          b.Interceptor,
+		DefaultHandlers:      append([]SendHandler(nil), b.DefaultHandlers...),
+		ImmutableSend:        b.ImmutableSend,
+		AutoSanitize:         b.AutoSanitize,
+		OnSent:               b.OnSent,
+		OnError:              b.OnError,
+		DefaultAt:            b.DefaultAt.Clone(),
+		httpClient:           b.httpClient,
+		afterSendHooks:       slices.Clone(b.afterSendHooks),
+		wrappers:             slices.Clone(b.wrappers),
+		debugAll:             b.debugAll,
+	}
+	if b.Template != nil {
+		// text/template 的 Clone 不会返回错误
+		c.Template, _ = b.Template.Clone()
+	}
+	if b.CircuitBreaker != nil {
+		c.CircuitBreaker = NewCircuitBreaker(b.CircuitBreaker.FailureThreshold, b.CircuitBreaker.HalfOpenAfter)
+	}
+	if b.rateLimiter != nil {
+		c.rateLimiter = rate.NewLimiter(b.rateLimiter.Limit(), b.rateLimiter.Burst())
+	}
+	if b.dedup != nil {
+		c.SuppressDuplicates(b.dedup.window)
+	}
+	if b.history != nil {
+		c.EnableHistory(0)
+	}
+	return c
+}
+
+// NewChild 基于当前机器人创建名称为 name 的子机器人，并在拷贝的配置上依次应用配置项，
+// 配置项返回错误时会引发恐慌，内置的配置项都不会返回错误
+func (b *Bot) NewChild(name string, overrides ...BotOption) *Bot {
+	if b == nil {
+		return nil
+	}
+	c := b.Clone()
+	c.Name = name
+	for _, option := range overrides {
+		if err := option(c); err != nil {
+			panic(fmt.Errorf("dingtalk: failed to apply option to child bot %q: %w", name, err))
+		}
+	}
+	return c
+}
+
+// Alias 返回名称为 name 的机器人拷贝，其余配置与当前机器人相同，详见 Clone
+func (b *Bot) Alias(name string) *Bot {
+	c := b.Clone()
+	if c != nil {
+		c.Name = name
+	}
+	return c
+}
+
+// Is 检测机器人名称是否与 name 相同，不区分大小写
+func (b *Bot) Is(name string) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return strings.EqualFold(b.Name, name)
+}
@@ -0,0 +1,230 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewChild(t *testing.T) {
+	parent := &Bot{Name: "base", Token: testToken, Secret: "SECsecret", Keywords: []string{"告警"}}
+	if err := parent.NewTemplate("Text.Content", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	child := parent.NewChild("db", WithBotSecret("SECchild"), func(b *Bot) error {
+		b.DefaultHandlers = append(b.DefaultHandlers, AtAll)
+		return nil
+	})
+	if child.Name != "db" || child.Token != testToken || child.Secret != "SECchild" || len(child.DefaultHandlers) != 1 {
+		t.Fatalf("NewChild() = %#v, overrides not applied", child)
+	}
+	if parent.Name != "base" || parent.Secret != "SECsecret" || len(parent.DefaultHandlers) != 0 {
+		t.Fatal("NewChild() modified the parent")
+	}
+
+	if err := child.NewTemplate("Text.Content", "child"); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := parent.ExecuteTemplate(&sb, "Text.Content", nil); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != "parent" {
+		t.Fatalf("parent template = %q after child override, want parent", sb.String())
+	}
+
+	parent.AddKeyword("通知")
+	parent.Keywords[0] = "changed"
+	if !reflect.DeepEqual(child.keywords(), []string{"告警"}) {
+		t.Fatalf("child keywords = %q, want [告警]", child.keywords())
+	}
+}
+
+func TestNewChildPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("NewChild() with failing option did not panic")
+		}
+	}()
+	(&Bot{}).NewChild("child", func(*Bot) error { return ErrMissingToken })
+}
+
+func TestCloneConcurrent(t *testing.T) {
+	bot := &Bot{Token: testToken}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			bot.Clone()
+		}()
+		go func() {
+			defer wg.Done()
+			bot.UnmarshalText([]byte("dingtalk://" + testToken + "?keywords=a&baseURL=http%3A%2F%2Flocalhost"))
+		}()
+		go func() {
+			defer wg.Done()
+			bot.AtMobiles("13800000000")
+		}()
+	}
+	wg.Wait()
+	if (*Bot)(nil).NewChild("nil") != nil {
+		t.Fatal("NewChild() on nil bot should return nil")
+	}
+}
+
+func TestCloneCopiedFields(t *testing.T) {
+	srv := newTestServer(t)
+	var debug strings.Builder
+	var hooks []string
+	bot := srv.Bot().WrapMsg(prefixText("[A] ")).AddAfterSendHook(func(context.Context, *Send, *SendResponse, time.Duration, error) {
+		hooks = append(hooks, "hook")
+	})
+	bot.DebugAll(&debug)
+
+	clone := bot.Clone().WrapMsg(prefixText("[B] ")).AddAfterSendHook(func(context.Context, *Send, *SendResponse, time.Duration, error) {
+		hooks = append(hooks, "clone")
+	})
+	if err := clone.Send(Text{Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != "[A] [B] hi" {
+		t.Fatalf("clone content = %v, want wrappers copied", got)
+	}
+	if !reflect.DeepEqual(hooks, []string{"hook", "clone"}) {
+		t.Fatalf("clone after send hooks = %q, want [hook clone]", hooks)
+	}
+	if !strings.Contains(debug.String(), "[A] [B] hi") {
+		t.Fatalf("clone debug output = %q, want DebugAll writer copied", debug.String())
+	}
+
+	hooks = nil
+	if err := bot.Send(Text{Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != "[A] hi" {
+		t.Fatalf("original content = %v, clone shares wrappers", got)
+	}
+	if !reflect.DeepEqual(hooks, []string{"hook"}) {
+		t.Fatalf("original after send hooks = %q, clone shares hooks", hooks)
+	}
+}
+
+func TestCloneResetFields(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.Keywords = []string{"告警"}
+	bot.LogKeywordInjections = true
+	if err := bot.Send(Text{Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	debug := bot.Debug(context.Background())
+	bot.PauseUntil(time.Now().Add(time.Hour)).Shutdown()
+
+	clone := bot.Clone()
+	if len(clone.KeywordLog) != 0 {
+		t.Fatalf("clone KeywordLog = %v, want empty", clone.KeywordLog)
+	}
+	if m := clone.Metrics().Snapshot(); m.Sent != 0 {
+		t.Fatalf("clone metrics = %+v, want zero", m)
+	}
+	if clone.IsPaused() || clone.IsShutdown() {
+		t.Fatal("clone copied the pause or shutdown state")
+	}
+	if err := clone.Send(Text{Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if debug.(*bytes.Buffer).Len() != 0 {
+		t.Fatal("clone consumed the one-shot Debug capture of the original")
+	}
+	if clone.watcher != nil {
+		t.Fatal("clone copied the config watcher")
+	}
+}
+
+func TestAlias(t *testing.T) {
+	bot := &Bot{Name: "alerts", Token: testToken, Secret: "SECsecret", Keywords: []string{"告警"}, DefaultAt: At{AtMobiles: []string{"13800000000"}}}
+	alias := bot.Alias("Backup")
+	if alias == bot || alias.Name != "Backup" || bot.Name != "alerts" {
+		t.Fatalf("Alias() name = %q, original = %q", alias.Name, bot.Name)
+	}
+	want := bot.Config()
+	want.Name = "Backup"
+	if !reflect.DeepEqual(alias.Config(), want) || !reflect.DeepEqual(alias.DefaultAt, bot.DefaultAt) {
+		t.Fatalf("Alias() = %#v, want the other fields equal to %#v", alias, bot)
+	}
+	alias.AddKeyword("通知")
+	if len(bot.keywords()) != 1 {
+		t.Fatal("Alias() shares keywords with the original")
+	}
+
+	if !alias.Is("backup") || !alias.Is("BACKUP") || alias.Is("alerts") || !bot.Is("Alerts") {
+		t.Fatal("Is() does not match names case-insensitively")
+	}
+	if (*Bot)(nil).Alias("nil") != nil || (*Bot)(nil).Is("") {
+		t.Fatal("Alias() or Is() on nil bot")
+	}
+}
+
+func TestNewBot(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+	keywords := []string{"告警"}
+	bot, err := NewBot(
+		WithName("alerts"),
+		WithBotToken(testToken),
+		WithBotSecret("SECsecret"),
+		WithKeywords(keywords),
+		WithTimeout(time.Second),
+		WithHTTPClient(client),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BotConfig{Name: "alerts", Token: testToken, Secret: "SECsecret", Keywords: []string{"告警"}, Timeout: time.Second}
+	if !reflect.DeepEqual(bot.Config(), want) || bot.HTTPClient() != client {
+		t.Fatalf("NewBot() = %#v, want options applied", bot)
+	}
+	keywords[0] = "changed"
+	if bot.Keywords[0] != "告警" {
+		t.Fatal("WithKeywords() shares the argument slice")
+	}
+}
+
+func TestNewBotErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []BotOption
+		want string
+	}{
+		{"missing token", nil, "dingtalk: missing token"},
+		{"long keyword", []BotOption{WithBotToken(testToken), WithKeywords([]string{strings.Repeat("k", MaxKeywordLen+1)})}, "too long"},
+		{"negative timeout", []BotOption{WithBotToken(testToken), WithTimeout(-time.Second)}, "negative timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, err := NewBot(tt.opts...)
+			if bot != nil || err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("NewBot() = %v, %v, want error containing %q", bot, err, tt.want)
+			}
+		})
+	}
+	if _, err := NewBot(WithBotToken(testToken), WithKeywords([]string{strings.Repeat("k", MaxKeywordLen)})); err != nil {
+		t.Fatalf("NewBot() with a %d byte keyword = %v", MaxKeywordLen, err)
+	}
+
+	errOption := errors.New("option failed")
+	var applied bool
+	_, err := NewBot(func(*Bot) error { return errOption }, func(*Bot) error {
+		applied = true
+		return nil
+	})
+	if !errors.Is(err, errOption) || applied {
+		t.Fatalf("NewBot() with a failing option = %v, applied later options = %v", err, applied)
+	}
+}
@@ -0,0 +1,88 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPaused 机器人已通过 PauseUntil 暂停发送
+var ErrPaused = errors.New("dingtalk: bot is paused")
+
+// PauseBehavior 机器人暂停期间发送消息的行为
+type PauseBehavior int
+
+const (
+	// PauseBlock 阻塞发送直到暂停结束或上下文取消
+	PauseBlock PauseBehavior = iota
+
+	// PauseReject 直接返回 ErrPaused
+	PauseReject
+)
+
+// PauseUntil 暂停发送直到 t ，暂停期间的发送行为由 PauseBehavior 决定，t 早于当前时间时相当于 Resume
+func (b *Bot) PauseUntil(t time.Time) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pausedUntil = t
+	// 唤醒正在等待的发送，使其重新读取截止时间
+	if b.resumed != nil {
+		close(b.resumed)
+	}
+	b.resumed = make(chan struct{})
+	return b
+}
+
+// Resume 立即结束暂停，正在阻塞的发送会继续进行
+func (b *Bot) Resume() *Bot {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pausedUntil = time.Time{}
+	if b.resumed != nil {
+		close(b.resumed)
+		b.resumed = nil
+	}
+	return b
+}
+
+// IsPaused 检测机器人当前是否处于暂停状态
+func (b *Bot) IsPaused() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return time.Now().Before(b.pausedUntil)
+}
+
+// waitPause 机器人暂停时根据 PauseBehavior 阻塞或返回 ErrPaused
+func (b *Bot) waitPause(ctx context.Context) error {
+	for {
+		b.mu.RLock()
+		until, resumed, behavior := b.pausedUntil, b.resumed, b.PauseBehavior
+		b.mu.RUnlock()
+		d := time.Until(until)
+		if d <= 0 {
+			return nil
+		}
+		if behavior == PauseReject {
+			return fmt.Errorf("%w until %s", ErrPaused, until.Format(time.RFC3339))
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("dingtalk: failed to wait for pause: %w", ctx.Err())
+		case <-resumed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
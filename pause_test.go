@@ -0,0 +1,79 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPauseReject(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	bot.PauseBehavior = PauseReject
+
+	bot.PauseUntil(time.Now().Add(time.Hour))
+	if !bot.IsPaused() {
+		t.Fatal("IsPaused() = false after PauseUntil")
+	}
+	if err := bot.SendText("paused"); !errors.Is(err, ErrPaused) {
+		t.Fatalf("SendText() while paused = %v, want ErrPaused", err)
+	}
+	bot.Resume()
+	if bot.IsPaused() {
+		t.Fatal("IsPaused() = true after Resume")
+	}
+	if err := bot.SendText("resumed"); err != nil {
+		t.Fatalf("SendText() after Resume = %v", err)
+	}
+	if bot.PauseUntil(time.Now().Add(-time.Minute)).IsPaused() {
+		t.Fatal("PauseUntil() in the past paused the bot")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+}
+
+func TestPauseBlock(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().PauseUntil(time.Now().Add(time.Hour))
+
+	done := make(chan error)
+	go func() { done <- bot.SendText("blocked") }()
+	select {
+	case err := <-done:
+		t.Fatalf("SendText() returned %v while paused, want it to block", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("server received %d requests while paused", n)
+	}
+	bot.Resume()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("SendText() after Resume = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendText() still blocked after Resume")
+	}
+
+	bot.PauseUntil(time.Now().Add(50 * time.Millisecond))
+	start := time.Now()
+	if err := bot.SendText("deadline"); err != nil {
+		t.Fatalf("SendText() after deadline = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("SendText() returned after %s, want it to wait for the deadline", elapsed)
+	}
+
+	bot.PauseUntil(time.Now().Add(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bot.SendWithContext(ctx, Text{Content: "cancelled"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendWithContext() with expired context = %v, want context.DeadlineExceeded", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Fatalf("server received %d requests, want 2", n)
+	}
+}
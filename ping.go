@@ -0,0 +1,29 @@
+package dingtalk
+
+import (
+	"context"
+	"time"
+)
+
+// pingContent 没有关键词时探测消息的内容
+const pingContent = "ping"
+
+// Ping 发送一条内容最少的文本类型消息以检测凭证和网络是否可用，内容为第一个关键词，没有关键词时为 ping ，
+// 探测消息不会@任何人，遵循 Bot.Timeout 的超时设置
+func (b *Bot) Ping(ctx context.Context) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	content := pingContent
+	if keywords := b.keywords(); len(keywords) != 0 {
+		content = keywords[0]
+	}
+	return b.SendWithContext(ctx, Text{Content: content}, AtClear)
+}
+
+// PingWithTimeout 在指定超时时间内检测凭证和网络是否可用，详见 Ping
+func (b *Bot) PingWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return b.Ping(ctx)
+}
@@ -0,0 +1,62 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().AtAll()
+	if err := bot.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() = %v", err)
+	}
+	body := srv.Last(t).JSON(t)
+	if body["msgtype"] != "text" || body["text"].(map[string]any)["content"] != pingContent {
+		t.Fatalf("Ping() sent %v, want text %q", body, pingContent)
+	}
+	if at, _ := body["at"].(map[string]any); at["isAtAll"] == true {
+		t.Fatal("Ping() applied DefaultAt")
+	}
+
+	bot.Keywords = []string{"监控", "告警"}
+	if err := bot.PingWithTimeout(time.Second); err != nil {
+		t.Fatalf("PingWithTimeout() = %v", err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != "监控" {
+		t.Fatalf("Ping() content = %v, want first keyword", got)
+	}
+
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	if err := bot.Ping(context.Background()); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Ping() = %v, want ErrInvalidToken", err)
+	}
+
+	if err := (&Bot{Token: TestWebhookToken}).Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() on test bot = %v", err)
+	}
+}
+
+func TestPingTimeout(t *testing.T) {
+	srv := newTestServer(t)
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	srv.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	})
+	bot := srv.Bot()
+	bot.Timeout = 20 * time.Millisecond
+	if err := bot.Ping(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Ping() with Bot.Timeout = %v, want context.DeadlineExceeded", err)
+	}
+	bot.Timeout = 0
+	if err := bot.PingWithTimeout(20 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PingWithTimeout() = %v, want context.DeadlineExceeded", err)
+	}
+}
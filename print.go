@@ -0,0 +1,96 @@
+package dingtalk
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// printMaxRunes 调试输出中文本内容的最大字符数
+const printMaxRunes = 50
+
+// PrintMsg 返回消息的可读摘要，消息实现了 fmt.Stringer 时使用其 String 方法
+func PrintMsg(msg Msg) string {
+	switch m := msg.(type) {
+	case nil:
+		return "<nil>"
+	case fmt.Stringer:
+		return m.String()
+	default:
+		return fmt.Sprintf("%s(%+v)", m.Type(), m)
+	}
+}
+
+func (t Text) String() string {
+	return fmt.Sprintf("Text(%q)", truncateRunes(t.Content, printMaxRunes))
+}
+
+func (l Link) String() string {
+	return fmt.Sprintf("Link(title=%q, url=%q)", l.Title, l.MessageURL)
+}
+
+func (m Markdown) String() string {
+	return fmt.Sprintf("Markdown(title=%q, text=%d chars)", m.Title, utf8.RuneCountInString(m.Text))
+}
+
+func (a ActionCard) String() string {
+	return fmt.Sprintf("ActionCard(title=%q, text=%d chars, button=%q)", a.Title, utf8.RuneCountInString(a.Text), a.SingleTitle)
+}
+
+func (a ActionsCard) String() string {
+	return fmt.Sprintf("ActionsCard(title=%q, text=%d chars, btns=%d)", a.Title, utf8.RuneCountInString(a.Text), len(a.Btns))
+}
+
+func (f FeedCard) String() string {
+	return fmt.Sprintf("FeedCard(links=%d)", len(f.Links))
+}
+
+var (
+	_ fmt.Stringer = Text{}
+	_ fmt.Stringer = Link{}
+	_ fmt.Stringer = Markdown{}
+	_ fmt.Stringer = ActionCard{}
+	_ fmt.Stringer = ActionsCard{}
+	_ fmt.Stringer = FeedCard{}
+)
+
+// printTokenLen 调试输出中凭证保留的最大字符数
+const printTokenLen = 8
+
+// tokenMask 替代凭证中被隐藏部分的字符串
+const tokenMask = "***"
+
+// maskToken 隐藏凭证，用于日志、调试输出和错误信息。凭证超过 8 个字符时保留前 8 个字符并以 *** 结尾，
+// 否则只返回 *** ，避免泄露完整的短凭证，凭证为空时返回空字符串
+func maskToken(token string) string {
+	switch {
+	case token == "":
+		return ""
+	case utf8.RuneCountInString(token) <= printTokenLen:
+		return tokenMask
+	default:
+		return string([]rune(token)[:printTokenLen]) + tokenMask
+	}
+}
+
+// String 返回机器人的可读摘要，凭证通过 maskToken 隐藏，不包含安全密钥
+func (b *Bot) String() string {
+	if b == nil {
+		return "Bot(nil)"
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return fmt.Sprintf("Bot{name=%q, token=%q, keywords=%q}", b.Name, maskToken(b.Token), b.Keywords)
+}
+
+// GoString 返回 %#v 格式的机器人摘要，与 String 一样不包含完整凭证和安全密钥
+func (b *Bot) GoString() string {
+	if b == nil {
+		return "(*dingtalk.Bot)(nil)"
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return fmt.Sprintf("dingtalk.Bot{Name: %q, Token: %q, Keywords: %#v}", b.Name, maskToken(b.Token), b.Keywords)
+}
+
+var _ fmt.Stringer = (*Bot)(nil)
+var _ fmt.GoStringer = (*Bot)(nil)
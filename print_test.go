@@ -0,0 +1,75 @@
+package dingtalk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// rawMsg 未实现 fmt.Stringer 的自定义消息
+type rawMsg struct{ Value string }
+
+func (rawMsg) Type() MsgType { return MsgText }
+
+func TestPrintMsg(t *testing.T) {
+	long := strings.Repeat("长", 60)
+	tests := []struct {
+		msg  Msg
+		want string
+	}{
+		{nil, "<nil>"},
+		{Text{Content: "hello"}, `Text("hello")`},
+		{Text{Content: long}, fmt.Sprintf("Text(%q)", strings.Repeat("长", 50)+"...")},
+		{Link{Title: "t", MessageURL: "https://example.com"}, `Link(title="t", url="https://example.com")`},
+		{Markdown{Title: "Alert", Text: strings.Repeat("a", 200)}, `Markdown(title="Alert", text=200 chars)`},
+		{Markdown{Title: "Alert", Text: long}, `Markdown(title="Alert", text=60 chars)`},
+		{ActionCard{Title: "t", Text: "正文", SingleTitle: "查看"}, `ActionCard(title="t", text=2 chars, button="查看")`},
+		{ActionsCard{Title: "t", Text: "text", Btns: make([]ActionCardBtn, 2)}, `ActionsCard(title="t", text=4 chars, btns=2)`},
+		{FeedCard{Links: feedLinks(3)}, `FeedCard(links=3)`},
+		{rawMsg{Value: "v"}, `text({Value:v})`},
+	}
+	for _, tt := range tests {
+		if got := PrintMsg(tt.msg); got != tt.want {
+			t.Errorf("PrintMsg(%T) = %s, want %s", tt.msg, got, tt.want)
+		}
+	}
+	if got := fmt.Sprint(Text{Content: "hello"}); got != `Text("hello")` {
+		t.Errorf("fmt.Sprint(Text) = %s", got)
+	}
+}
+
+func TestBotString(t *testing.T) {
+	bot := &Bot{Name: "alerts", Token: testToken, Secret: "SECsecret", Keywords: []string{"ALERT"}}
+	outputs := map[string]string{
+		"%v":  fmt.Sprintf("%v", bot),
+		"%s":  fmt.Sprintf("%s", bot),
+		"%#v": fmt.Sprintf("%#v", bot),
+	}
+	want := map[string]string{
+		"%v":  `Bot{name="alerts", token="01234567***", keywords=["ALERT"]}`,
+		"%s":  `Bot{name="alerts", token="01234567***", keywords=["ALERT"]}`,
+		"%#v": `dingtalk.Bot{Name: "alerts", Token: "01234567***", Keywords: []string{"ALERT"}}`,
+	}
+	for verb, got := range outputs {
+		if got != want[verb] {
+			t.Errorf("Sprintf(%q) = %s, want %s", verb, got, want[verb])
+		}
+		if strings.Contains(got, testToken) || strings.Contains(got, "SECsecret") {
+			t.Errorf("Sprintf(%q) = %s leaks the token or secret", verb, got)
+		}
+	}
+	// 不超过 8 个字符的凭证也不会泄露
+	short := &Bot{Name: "short", Token: "abc12345"}
+	if got := fmt.Sprint(short); got != `Bot{name="short", token="***", keywords=[]}` {
+		t.Errorf("fmt.Sprint(short token) = %s", got)
+	}
+	if got := fmt.Sprintf("%#v", short); strings.Contains(got, "abc12345") {
+		t.Errorf("Sprintf(%%#v, short token) = %s leaks the token", got)
+	}
+	if got := fmt.Sprint((*Bot)(nil)); got != "Bot(nil)" {
+		t.Errorf("fmt.Sprint(nil) = %s", got)
+	}
+	if got := fmt.Sprintf("%#v", (*Bot)(nil)); got != "(*dingtalk.Bot)(nil)" {
+		t.Errorf("Sprintf(%%#v, nil) = %s", got)
+	}
+}
@@ -0,0 +1,71 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultProgressInterval 进度消息默认的最小发送间隔
+const DefaultProgressInterval = 10 * time.Second
+
+// ProgressSender 以 markdown 类型消息发送任务进度，钉钉不支持编辑消息，因此每次更新都会发送一条新消息
+type ProgressSender struct {
+	// 两次进度更新之间的最小间隔，值不为正时使用 DefaultProgressInterval
+	Interval time.Duration
+
+	bot   *Bot
+	ctx   context.Context
+	title string
+	total int
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewProgressSender 新建进度发送器
+func NewProgressSender(bot *Bot, title string, total int) *ProgressSender {
+	return &ProgressSender{bot: bot, ctx: context.Background(), title: title, total: total}
+}
+
+// SendProgress 新建使用 ctx 发送消息的进度发送器，详见 NewProgressSender
+func (b *Bot) SendProgress(ctx context.Context, title string, total int) *ProgressSender {
+	p := NewProgressSender(b, title, total)
+	p.ctx = ctx
+	return p
+}
+
+// send 发送形如 [done/total] title 的进度消息
+func (p *ProgressSender) send(done int, note string) error {
+	text := fmt.Sprintf("[%d/%d] %s", done, p.total, p.title)
+	if note != "" {
+		text += "\n\n" + note
+	}
+	return p.bot.SendWithContext(p.ctx, Markdown{Title: p.title, Text: text})
+}
+
+// Update 发送进度消息，距离上次发送不足 Interval 时忽略本次更新并返回空
+func (p *ProgressSender) Update(done int, note string) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	p.mu.Lock()
+	now := time.Now()
+	if !p.last.IsZero() && now.Sub(p.last) < interval {
+		p.mu.Unlock()
+		return nil
+	}
+	p.last = now
+	p.mu.Unlock()
+	return p.send(done, note)
+}
+
+// Finish 不受发送间隔限制地发送完成消息
+func (p *ProgressSender) Finish(msg string) error {
+	p.mu.Lock()
+	p.last = time.Now()
+	p.mu.Unlock()
+	return p.send(p.total, msg)
+}
@@ -0,0 +1,58 @@
+package dingtalk
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// markdownText 返回请求体中 markdown 类型消息的正文
+func markdownText(t *testing.T, r testRequest) string {
+	t.Helper()
+	text, _ := r.JSON(t)["markdown"].(map[string]any)["text"].(string)
+	return text
+}
+
+func TestProgressSender(t *testing.T) {
+	srv := newTestServer(t)
+	p := srv.Bot().SendProgress(context.Background(), "Migration in progress", 100)
+	p.Interval = 50 * time.Millisecond
+
+	if err := p.Update(40, "users"); err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if got := markdownText(t, srv.Last(t)); got != "[40/100] Migration in progress\n\nusers" {
+		t.Fatalf("progress text = %q", got)
+	}
+	for done := 41; done < 50; done++ {
+		if err := p.Update(done, ""); err != nil {
+			t.Fatalf("throttled Update() = %v", err)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests within the interval, want 1", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := p.Update(60, ""); err != nil {
+		t.Fatalf("Update() after interval = %v", err)
+	}
+	if got := markdownText(t, srv.Last(t)); got != "[60/100] Migration in progress" {
+		t.Fatalf("progress text = %q", got)
+	}
+
+	if err := p.Finish("done"); err != nil {
+		t.Fatalf("Finish() = %v", err)
+	}
+	requests := srv.Requests()
+	if len(requests) != 3 || markdownText(t, requests[2]) != "[100/100] Migration in progress\n\ndone" {
+		t.Fatalf("Finish() was throttled or sent %q", markdownText(t, requests[len(requests)-1]))
+	}
+	if title := requests[2].JSON(t)["markdown"].(map[string]any)["title"]; title != "Migration in progress" {
+		t.Fatalf("title = %v", title)
+	}
+	p.Update(100, "")
+	if n := len(srv.Requests()); n != 3 {
+		t.Fatal("Update() right after Finish was not throttled")
+	}
+}
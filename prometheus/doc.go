@@ -0,0 +1,2 @@
+// Package prometheus 为钉钉机器人提供 Prometheus 指标收集器，只有导入该包时才会依赖 client_golang
+package prometheus
@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	"github.com/Drelf2018/dingtalk"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collector 机器人发送消息的 Prometheus 指标收集器
+type collector struct {
+	sends    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewCollector 新建机器人的指标收集器，收集器通过 Bot.AddAfterSendHook 记录每次发送，不会修改机器人的 Callbacks
+//
+//	sends_total{msg_type,result}       // 发送次数，result 为 success 或 failure
+//	send_duration_seconds{msg_type}    // 发送耗时
+func NewCollector(bot *dingtalk.Bot, namespace, subsystem string) prometheus.Collector {
+	c := &collector{
+		sends: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "sends_total",
+			Help:      "Total number of DingTalk messages sent.",
+		}, []string{"msg_type", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "send_duration_seconds",
+			Help:      "Duration of DingTalk send requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"msg_type"}),
+	}
+	bot.AddAfterSendHook(c.observe)
+	return c
+}
+
+// observe 记录一次发送
+func (c *collector) observe(_ context.Context, api *dingtalk.Send, _ *dingtalk.SendResponse, latency time.Duration, err error) {
+	var msgType string
+	if api.Msg != nil {
+		msgType = string(api.Msg.Type())
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	c.sends.WithLabelValues(msgType, result).Inc()
+	c.duration.WithLabelValues(msgType).Observe(latency.Seconds())
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	c.sends.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.sends.Collect(ch)
+	c.duration.Collect(ch)
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+// EnableMetrics 为机器人创建并注册指标收集器，注册器为空时使用 prometheus.DefaultRegisterer
+func EnableMetrics(bot *dingtalk.Bot, reg prometheus.Registerer, namespace, subsystem string) error {
+	if bot == nil {
+		return dingtalk.ErrNilBot
+	}
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	return reg.Register(NewCollector(bot, namespace, subsystem))
+}
+
+// WithMetrics 为机器人注册命名空间为 dingtalk 的指标收集器，与 EnableMetrics 相同，但注册失败时会引发恐慌
+func WithMetrics(bot *dingtalk.Bot, reg prometheus.Registerer) *dingtalk.Bot {
+	if err := EnableMetrics(bot, reg, "dingtalk", ""); err != nil {
+		panic(err)
+	}
+	return bot
+}
@@ -0,0 +1,123 @@
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Drelf2018/dingtalk"
+	dtprometheus "github.com/Drelf2018/dingtalk/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newBot 返回向测试服务器发送消息的机器人，服务器对内容为 fail 的消息返回错误码
+func newBot(t *testing.T) *dingtalk.Bot {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if string(body) == `{"msgtype":"text","text":{"content":"fail"}}` {
+			io.WriteString(w, `{"errcode":300001,"errmsg":"token is not exist"}`)
+			return
+		}
+		io.WriteString(w, `{"errcode":0,"errmsg":"ok"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return &dingtalk.Bot{Token: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", BaseURL: srv.URL}
+}
+
+func TestNewCollector(t *testing.T) {
+	bot := newBot(t)
+	c := dtprometheus.NewCollector(bot, "dingtalk", "")
+	// 收集器不依赖 Callbacks ，替换回调函数后仍能记录
+	var called int
+	bot.WithCallbacks(dingtalk.BotCallbacks{
+		AfterSend: func(context.Context, *dingtalk.Send, *dingtalk.SendResponse, time.Duration, error) { called++ },
+	})
+
+	ctx := context.Background()
+	for _, msg := range []dingtalk.Msg{
+		dingtalk.Text{Content: "a"},
+		dingtalk.Text{Content: "b"},
+		dingtalk.Markdown{Title: "t", Text: "c"},
+		dingtalk.Text{Content: "fail"},
+	} {
+		bot.SendWithContext(ctx, msg)
+	}
+	if called != 4 {
+		t.Fatalf("AfterSend callback called %d times, want 4", called)
+	}
+
+	f, err := os.Open("testdata/sends_total.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err = testutil.CollectAndCompare(c, f, "dingtalk_sends_total"); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(c, "dingtalk_send_duration_seconds"); n != 2 {
+		t.Fatalf("send_duration_seconds has %d series, want 2", n)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	bot := newBot(t)
+	reg := prometheus.NewPedanticRegistry()
+	dtprometheus.WithMetrics(bot, reg)
+	if err := bot.SendWithContext(context.Background(), dingtalk.Text{Content: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := testutil.GatherAndCount(reg, "dingtalk_sends_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("dingtalk_sends_total has %d series, want 1", n)
+	}
+
+	defer func() {
+		var are prometheus.AlreadyRegisteredError
+		if r := recover(); r == nil || !errors.As(r.(error), &are) {
+			t.Fatalf("second WithMetrics() recovered %v, want AlreadyRegisteredError", r)
+		}
+	}()
+	dtprometheus.WithMetrics(bot, reg)
+}
+
+func TestEnableMetrics(t *testing.T) {
+	// 替换默认注册器，使测试可以重复运行
+	reg := prometheus.NewRegistry()
+	registerer, gatherer := prometheus.DefaultRegisterer, prometheus.DefaultGatherer
+	prometheus.DefaultRegisterer, prometheus.DefaultGatherer = reg, reg
+	t.Cleanup(func() { prometheus.DefaultRegisterer, prometheus.DefaultGatherer = registerer, gatherer })
+
+	bot := newBot(t)
+	if err := dtprometheus.EnableMetrics(bot, nil, "dingtalk_enable_test", "bot"); err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []string{"a", "b", "fail"} {
+		bot.SendWithContext(context.Background(), dingtalk.Text{Content: content})
+	}
+	n, err := testutil.GatherAndCount(prometheus.DefaultGatherer, "dingtalk_enable_test_bot_sends_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("dingtalk_enable_test_bot_sends_total has %d series in the default gatherer, want 2", n)
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if err := dtprometheus.EnableMetrics(bot, nil, "dingtalk_enable_test", "bot"); !errors.As(err, &are) {
+		t.Fatalf("second EnableMetrics() = %v, want AlreadyRegisteredError", err)
+	}
+	if err := dtprometheus.EnableMetrics(nil, reg, "nil", ""); !errors.Is(err, dingtalk.ErrNilBot) {
+		t.Fatalf("EnableMetrics() with nil bot = %v, want ErrNilBot", err)
+	}
+}
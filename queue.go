@@ -0,0 +1,207 @@
+package dingtalk
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 消息优先级，数值越小优先级越高
+const (
+	PriorityHigh   = 0
+	PriorityNormal = 5
+	PriorityLow    = 10
+)
+
+// ErrQueueFull 发送队列已满
+var ErrQueueFull = errors.New("dingtalk: send queue is full")
+
+// ErrQueueClosed 发送队列已关闭
+var ErrQueueClosed = errors.New("dingtalk: send queue is closed")
+
+// queueItem 发送队列中的消息
+type queueItem struct {
+	priority int
+	seq      uint64
+	msg      Msg
+	handlers []SendHandler
+}
+
+// queueHeap 以优先级为序的最小堆，优先级相同时先进先出
+type queueHeap []*queueItem
+
+func (h queueHeap) Len() int { return len(h) }
+
+func (h queueHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h queueHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *queueHeap) Push(x any) { *h = append(*h, x.(*queueItem)) }
+
+func (h *queueHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// SendQueue 带优先级的异步发送队列
+type SendQueue struct {
+	// 发送失败时调用，其中的恐慌会被恢复并使用机器人的记录器记录
+	OnError func(msg Msg, err error)
+
+	bot     *Bot
+	size    int
+	workers int
+	items   queueHeap
+	seq     uint64
+	closed  bool
+
+	processing atomic.Int64
+	completed  atomic.Uint64
+	failed     atomic.Uint64
+
+	lastErr     error
+	lastErrTime time.Time
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	wg   sync.WaitGroup
+}
+
+// NewSendQueue 新建发送队列并启动 workers 个协程发送消息，size 为队列容量，值不为正时不限制容量
+func NewSendQueue(bot *Bot, workers, size int) *SendQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+	q := &SendQueue{bot: bot, size: size, workers: workers}
+	q.cond = sync.NewCond(&q.mu)
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+	return q
+}
+
+// worker 持续取出优先级最高的消息并发送，队列关闭且清空后退出
+func (q *SendQueue) worker() {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		item := heap.Pop(&q.items).(*queueItem)
+		q.processing.Add(1)
+		q.mu.Unlock()
+
+		err := q.bot.SendWithContext(context.Background(), item.msg, item.handlers...)
+		q.processing.Add(-1)
+		if err == nil {
+			q.completed.Add(1)
+			continue
+		}
+		q.failed.Add(1)
+		q.mu.Lock()
+		q.lastErr, q.lastErrTime = err, time.Now()
+		q.mu.Unlock()
+		if hook := q.OnError; hook != nil {
+			q.bot.callHook("SendQueue.OnError", func() { hook(item.msg, err) })
+		}
+	}
+}
+
+// PushWithPriority 以指定优先级将消息加入队列，数值越小越先发送
+func (q *SendQueue) PushWithPriority(priority int, msg Msg, handlers ...SendHandler) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.size > 0 && len(q.items) >= q.size {
+		return ErrQueueFull
+	}
+	q.seq++
+	heap.Push(&q.items, &queueItem{priority: priority, seq: q.seq, msg: msg, handlers: handlers})
+	q.cond.Signal()
+	return nil
+}
+
+// Push 以普通优先级将消息加入队列
+func (q *SendQueue) Push(msg Msg, handlers ...SendHandler) error {
+	return q.PushWithPriority(PriorityNormal, msg, handlers...)
+}
+
+// Close 关闭队列，不再接受新消息，并等待队列中剩余的消息发送完毕
+func (q *SendQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+// SendQueueStats 发送队列的统计快照
+type SendQueueStats struct {
+	// 队列中等待发送的消息数
+	Pending int
+
+	// 正在发送的消息数
+	Processing int
+
+	// 发送成功的消息数
+	Completed uint64
+
+	// 发送失败的消息数
+	Failed uint64
+
+	// 发送协程数
+	Workers int
+
+	// 最近一次发送失败的错误
+	LastError error
+
+	// 最近一次发送失败的时间
+	LastErrorTime time.Time
+}
+
+// Stats 返回发送队列当前的统计快照
+func (q *SendQueue) Stats() SendQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return SendQueueStats{
+		Pending:       len(q.items),
+		Processing:    int(q.processing.Load()),
+		Completed:     q.completed.Load(),
+		Failed:        q.failed.Load(),
+		Workers:       q.workers,
+		LastError:     q.lastErr,
+		LastErrorTime: q.lastErrTime,
+	}
+}
+
+// queueErrorCooldown 最近一次发送失败后队列被视为不健康的时长
+const queueErrorCooldown = time.Minute
+
+// IsHealthy 检测队列是否健康，即等待发送的消息数少于容量的一半，且一分钟内没有发送失败，不限制容量时只检测后者
+func (q *SendQueue) IsHealthy() bool {
+	stats := q.Stats()
+	if q.size > 0 && stats.Pending >= q.size/2 {
+		return false
+	}
+	return stats.LastError == nil || time.Since(stats.LastErrorTime) > queueErrorCooldown
+}
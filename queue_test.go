@@ -0,0 +1,176 @@
+package dingtalk
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendQueuePriority(t *testing.T) {
+	srv := newTestServer(t)
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	srv.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+			<-unblock
+		default:
+		}
+		replyError(0, "ok")(w, r)
+	})
+	q := NewSendQueue(srv.Bot(), 1, 0)
+	// 第一条消息阻塞唯一的发送协程，之后加入的消息按优先级排序
+	if err := q.Push(Text{Content: "blocker"}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := q.PushWithPriority(PriorityLow, Text{Content: "low"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Push(Text{Content: "normal"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.PushWithPriority(PriorityHigh, Text{Content: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.PushWithPriority(PriorityHigh, Text{Content: "high2"}); err != nil {
+		t.Fatal(err)
+	}
+	close(unblock)
+	q.Close()
+
+	var got []string
+	for _, r := range srv.Requests() {
+		got = append(got, r.JSON(t)["text"].(map[string]any)["content"].(string))
+	}
+	want := []string{"blocker", "high", "high2", "normal", "low"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("send order = %q, want %q", got, want)
+	}
+	if err := q.Push(Text{Content: "closed"}); err != ErrQueueClosed {
+		t.Fatalf("Push() after Close error = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestSendQueueFull(t *testing.T) {
+	srv := newTestServer(t)
+	unblock := make(chan struct{})
+	srv.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		replyError(0, "ok")(w, r)
+	})
+	q := NewSendQueue(srv.Bot(), 1, 1)
+	defer q.Close()
+	defer close(unblock)
+	var full bool
+	for i := 0; i < 3 && !full; i++ {
+		full = q.Push(Text{Content: "msg"}) == ErrQueueFull
+	}
+	if !full {
+		t.Fatal("Push() never returned ErrQueueFull")
+	}
+}
+
+func TestSendQueueStats(t *testing.T) {
+	srv := newTestServer(t)
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	srv.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+			<-unblock
+		default:
+		}
+		replyError(0, "ok")(w, r)
+	})
+	q := NewSendQueue(srv.Bot(), 1, 4)
+	if !q.IsHealthy() {
+		t.Fatal("IsHealthy() = false for an empty queue")
+	}
+	for _, content := range []string{"blocker", "second", "third"} {
+		if err := q.Push(Text{Content: content}); err != nil {
+			t.Fatal(err)
+		}
+		if content == "blocker" {
+			<-started
+		}
+	}
+	stats := q.Stats()
+	if stats.Pending != 2 || stats.Processing != 1 || stats.Workers != 1 || stats.Completed != 0 {
+		t.Fatalf("Stats() during processing = %+v, want 2 pending and 1 processing", stats)
+	}
+	if q.IsHealthy() {
+		t.Fatal("IsHealthy() = true with half of the buffer pending")
+	}
+	close(unblock)
+	q.Close()
+	stats = q.Stats()
+	if stats.Pending != 0 || stats.Processing != 0 || stats.Completed != 3 || stats.Failed != 0 {
+		t.Fatalf("Stats() after Close = %+v, want 3 completed", stats)
+	}
+	if !q.IsHealthy() {
+		t.Fatal("IsHealthy() = false after all messages completed")
+	}
+}
+
+func TestSendQueueStatsError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	q := NewSendQueue(srv.Bot(), 2, 0)
+	for i := 0; i < 3; i++ {
+		if err := q.Push(Text{Content: "fail"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Close()
+	stats := q.Stats()
+	if stats.Failed != 3 || stats.Completed != 0 || stats.Workers != 2 {
+		t.Fatalf("Stats() = %+v, want 3 failed", stats)
+	}
+	if !errors.Is(stats.LastError, ErrInvalidToken) || time.Since(stats.LastErrorTime) > time.Minute {
+		t.Fatalf("LastError = %v at %s, want a recent ErrInvalidToken", stats.LastError, stats.LastErrorTime)
+	}
+	if q.IsHealthy() {
+		t.Fatal("IsHealthy() = true right after a failure")
+	}
+	q.mu.Lock()
+	q.lastErrTime = time.Now().Add(-queueErrorCooldown - time.Second)
+	q.mu.Unlock()
+	if !q.IsHealthy() {
+		t.Fatal("IsHealthy() = false after the error cooldown")
+	}
+}
+
+func TestSendQueueOnErrorPanic(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "token is not exist"))
+	var buf bytes.Buffer
+	q := NewSendQueue(srv.Bot().WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))), 1, 0)
+	var calls atomic.Int32
+	q.OnError = func(Msg, error) {
+		calls.Add(1)
+		panic("on error")
+	}
+	for i := 0; i < 2; i++ {
+		if err := q.Push(Text{Content: "fail"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Close()
+	// 恐慌被恢复后发送协程继续处理之后的消息
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("OnError called %d times, want 2", n)
+	}
+	if stats := q.Stats(); stats.Failed != 2 {
+		t.Fatalf("Stats() = %+v, want 2 failed", stats)
+	}
+	if logs := buf.String(); !strings.Contains(logs, `"hook":"SendQueue.OnError"`) {
+		t.Fatalf("panic not logged: %s", logs)
+	}
+}
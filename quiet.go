@@ -0,0 +1,39 @@
+package dingtalk
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrQuietWindow 当前处于免打扰时段
+var ErrQuietWindow = errors.New("dingtalk: in quiet window")
+
+// Quiet 在 [start, end) 时间段内阻止发送并返回 ErrQuietWindow
+func Quiet(start, end time.Time) SendHandler {
+	return func(*Send) error {
+		t := time.Now()
+		if !t.Before(start) && t.Before(end) {
+			return ErrQuietWindow
+		}
+		return nil
+	}
+}
+
+// QuietHours 在本地时间每天的 [startHour, endHour) 小时内阻止发送并返回 ErrQuietWindow ，
+// 小时使用 24 小时制，开始小时大于结束小时时表示跨越午夜，两者相等时不阻止发送
+func QuietHours(startHour, endHour int) SendHandler {
+	return func(*Send) error {
+		if inQuietHours(time.Now().Hour(), startHour, endHour) {
+			return ErrQuietWindow
+		}
+		return nil
+	}
+}
+
+// inQuietHours 检测小时 h 是否处于 [startHour, endHour) 内，详见 QuietHours
+func inQuietHours(h, startHour, endHour int) bool {
+	if startHour <= endHour {
+		return startHour <= h && h < endHour
+	}
+	return h >= startHour || h < endHour
+}
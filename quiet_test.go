@@ -0,0 +1,64 @@
+package dingtalk
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuiet(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	now := time.Now()
+
+	if err := bot.SendText("inside", Quiet(now.Add(-time.Hour), now.Add(time.Hour))); !errors.Is(err, ErrQuietWindow) {
+		t.Fatalf("SendText() inside window = %v, want ErrQuietWindow", err)
+	}
+	if err := bot.SendText("before", Quiet(now.Add(time.Hour), now.Add(2*time.Hour))); err != nil {
+		t.Fatalf("SendText() before window = %v", err)
+	}
+	if err := bot.SendText("after", Quiet(now.Add(-2*time.Hour), now.Add(-time.Hour))); err != nil {
+		t.Fatalf("SendText() after window = %v", err)
+	}
+
+	bot.DefaultHandlers = []SendHandler{Quiet(now.Add(-time.Hour), now.Add(time.Hour))}
+	if err := bot.SendText("default"); !errors.Is(err, ErrQuietWindow) {
+		t.Fatalf("SendText() with default quiet handler = %v, want ErrQuietWindow", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Fatalf("server received %d requests, want 2", n)
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	tests := []struct {
+		h, start, end int
+		want          bool
+	}{
+		{9, 9, 18, true},
+		{17, 9, 18, true},
+		{18, 9, 18, false},
+		{8, 9, 18, false},
+		{23, 22, 6, true},
+		{0, 22, 6, true},
+		{5, 22, 6, true},
+		{6, 22, 6, false},
+		{12, 22, 6, false},
+		{12, 12, 12, false},
+	}
+	for _, tt := range tests {
+		if got := inQuietHours(tt.h, tt.start, tt.end); got != tt.want {
+			t.Errorf("inQuietHours(%d, %d, %d) = %v, want %v", tt.h, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	h := time.Now().Hour()
+	if err := QuietHours(h, (h+1)%24)(&Send{}); !errors.Is(err, ErrQuietWindow) {
+		t.Fatalf("QuietHours(%d, %d) = %v, want ErrQuietWindow", h, (h+1)%24, err)
+	}
+	if err := QuietHours((h+1)%24, (h+2)%24)(&Send{}); err != nil {
+		t.Fatalf("QuietHours(%d, %d) = %v, want nil", (h+1)%24, (h+2)%24, err)
+	}
+}
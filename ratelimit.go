@@ -0,0 +1,125 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultPerMinute 平台规定的每分钟发送消息上限
+const DefaultPerMinute = 20
+
+// RateLimit 设置令牌桶限流器，发送消息前会等待令牌，等待期间上下文取消则返回错误
+func (b *Bot) RateLimit(r rate.Limit, burst int) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.rateLimiter = rate.NewLimiter(r, burst)
+	return b
+}
+
+// ThrottlePerMinute 设置每分钟最多发送 n 条消息的令牌桶限流器，值不为正时使用 DefaultPerMinute ，
+// 超过平台限制会收到错误码 43 ，因此建议不超过 20
+func (b *Bot) ThrottlePerMinute(n int) *Bot {
+	if n <= 0 {
+		n = DefaultPerMinute
+	}
+	return b.RateLimit(rate.Every(time.Minute/time.Duration(n)), n)
+}
+
+// ThrottlePerSecond 设置每秒最多发送 n 条消息的令牌桶限流器，值不为正时为 1 ，
+// 注意每秒的限制仍可能使一分钟内的发送量超过平台限制，且会替换 ThrottlePerMinute 设置的限流器
+func (b *Bot) ThrottlePerSecond(n int) *Bot {
+	if n <= 0 {
+		n = 1
+	}
+	return b.RateLimit(rate.Limit(n), n)
+}
+
+// DisableRateLimit 移除令牌桶限流器
+func (b *Bot) DisableRateLimit() *Bot {
+	if b == nil {
+		return nil
+	}
+	b.rateLimiter = nil
+	return b
+}
+
+// RateLimiter 滑动窗口限流器，任意 per 时长内最多允许 n 次发送，可以安全地并发使用
+type RateLimiter struct {
+	n     int
+	per   time.Duration
+	times []time.Time // 最近 n 次发送的时间，按环形缓冲区存储
+	next  int         // 最早一次发送在环形缓冲区中的位置
+
+	mu sync.Mutex
+}
+
+// NewRateLimiter 新建任意 per 时长内最多允许 n 次发送的限流器，n 不为正时使用 DefaultPerMinute ，per 不为正时为一分钟
+func NewRateLimiter(n int, per time.Duration) *RateLimiter {
+	if n <= 0 {
+		n = DefaultPerMinute
+	}
+	if per <= 0 {
+		per = time.Minute
+	}
+	return &RateLimiter{n: n, per: per, times: make([]time.Time, n)}
+}
+
+// reserve 窗口内有空余时记录本次发送并返回零，否则返回需要等待的时长
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if wait := l.times[l.next].Add(l.per).Sub(now); wait > 0 {
+		return wait
+	}
+	l.times[l.next] = now
+	l.next = (l.next + 1) % l.n
+	return 0
+}
+
+// Wait 阻塞直到窗口内有空余，上下文取消时返回错误
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait == 0 {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("dingtalk: failed to wait for rate limiter: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Handler 返回发送前等待限流器的处理器，处理器只记录限流器，等待在通过@、去重和熔断检测后、发出请求前进行，
+// 因此 NewSend 和 Serialize 不会阻塞或消耗配额，等待时使用发送的上下文
+func (l *RateLimiter) Handler() SendHandler {
+	return func(s *Send) error {
+		s.limiters = append(s.limiters, l)
+		return nil
+	}
+}
+
+// waitLimiters 依次等待请求的所有限流器
+func (s *Send) waitLimiters(ctx context.Context) error {
+	for _, l := range s.limiters {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RateLimit 新建任意 per 时长内最多允许 n 次发送的限流处理器，超出时阻塞直到可以发送，
+// 平台规定每分钟最多发送 20 条消息，超出时返回错误码 130101 ，同一个处理器可以在多个机器人和协程间共用
+func RateLimit(n int, per time.Duration) SendHandler {
+	return NewRateLimiter(n, per).Handler()
+}
@@ -0,0 +1,239 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestBotRateLimit(t *testing.T) {
+	srv := newTestServer(t)
+	const burst = 2
+	interval := 200 * time.Millisecond
+	bot := srv.Bot().RateLimit(rate.Every(interval), burst)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < burst; i++ {
+		if err := bot.SendWithContext(ctx, Text{Content: "burst"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= interval/2 {
+		t.Fatalf("burst sends took %s, want no wait", elapsed)
+	}
+	if err := bot.SendWithContext(ctx, Text{Content: "blocked"}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < interval*3/4 {
+		t.Fatalf("send %d took %s, want blocking about %s", burst+1, elapsed, interval)
+	}
+	if n := len(srv.Requests()); n != burst+1 {
+		t.Fatalf("server received %d requests, want %d", n, burst+1)
+	}
+}
+
+func TestBotRateLimitCancel(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().RateLimit(rate.Every(time.Hour), 1)
+	if err := bot.SendWithContext(context.Background(), Text{Content: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bot.SendWithContext(ctx, Text{Content: "waiting"}) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("SendWithContext() error = nil, want rate limiter error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not unblock the waiting send")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+}
+
+func TestDisableRateLimit(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().RateLimit(rate.Every(time.Hour), 1).DisableRateLimit()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if err := bot.SendWithContext(ctx, Text{Content: "free"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestThrottlePerMinute(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().ThrottlePerMinute(0)
+	if got := bot.rateLimiter; got.Burst() != DefaultPerMinute || got.Limit() != rate.Every(time.Minute/DefaultPerMinute) {
+		t.Fatalf("ThrottlePerMinute(0) limiter = %v/%d, want %d per minute", got.Limit(), got.Burst(), DefaultPerMinute)
+	}
+
+	bot.ThrottlePerMinute(3)
+	for i := 0; i < 3; i++ {
+		if err := bot.SendText("burst"); err != nil {
+			t.Fatalf("send %d within burst = %v", i, err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := bot.SendWithContext(ctx, Text{Content: "blocked"}); err == nil {
+		t.Fatal("send exceeding the per-minute rate = nil, want blocked until the deadline")
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Fatalf("server received %d requests, want 3", n)
+	}
+}
+
+func TestThrottlePerSecond(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().ThrottlePerSecond(5)
+	for i := 0; i < 5; i++ {
+		bot.SendText("burst")
+	}
+	start := time.Now()
+	if err := bot.SendText("refilled"); err != nil {
+		t.Fatalf("send after refill = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("sixth send returned after %s, want it to wait for the bucket to refill", elapsed)
+	}
+}
+
+// replyQuota 返回窗口 per 内超过 n 次请求时回复错误码 130101 的响应
+func replyQuota(n int, per time.Duration) http.HandlerFunc {
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		now := time.Now()
+		for len(times) > 0 && now.Sub(times[0]) >= per {
+			times = times[1:]
+		}
+		times = append(times, now)
+		exceeded := len(times) > n
+		mu.Unlock()
+		if exceeded {
+			replyError(ErrSendTooFast.Code, "send too fast")(w, r)
+			return
+		}
+		replyError(0, "ok")(w, r)
+	}
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	srv := newTestServer(t)
+	const per = 300 * time.Millisecond
+	// 服务端的窗口略短于限流器，避免计时误差导致误判
+	srv.SetHandler(replyQuota(DefaultPerMinute, per-50*time.Millisecond))
+	bot := srv.Bot()
+	limit := RateLimit(DefaultPerMinute, per)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make([]error, 25)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = bot.SendText("rapid", limit)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("send %d = %v, want no 130101 error", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < per {
+		t.Fatalf("25 sends took %s, want the last 5 to wait for the window of %s", elapsed, per)
+	}
+	if n := len(srv.Requests()); n != 25 {
+		t.Fatalf("server received %d requests, want 25", n)
+	}
+}
+
+func TestRateLimitHandlerCancel(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	limit := RateLimit(1, time.Hour)
+	if err := bot.SendText("first", limit); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bot.SendWithContext(ctx, Text{Content: "waiting"}, limit) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("SendWithContext() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not unblock the waiting send")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+
+	l := NewRateLimiter(1, time.Hour)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() on a full window = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimitHandlerQuota(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().SuppressDuplicates(time.Minute).WithCircuitBreaker(1, time.Hour)
+	limit := RateLimit(1, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if _, err := bot.NewSend(ctx, Text{Content: "built"}, limit); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bot.Serialize(ctx, Text{Content: "built"}, limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bot.SendWithContext(ctx, Link{Title: "t", MessageURL: "https://example.com"}, AtAll, limit); !errors.Is(err, ErrAtNotSupported) {
+		t.Fatalf("SendWithContext() with at on a link = %v, want ErrAtNotSupported", err)
+	}
+	if err := bot.SendWithContext(ctx, Text{Content: "sent"}, limit); err != nil {
+		t.Fatalf("send after NewSend, Serialize and a rejected send = %v, want the quota unused", err)
+	}
+	short, stop := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer stop()
+	if err := bot.SendWithContext(short, Text{Content: "sent"}, limit); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate send = %v, want ErrDuplicate without waiting", err)
+	}
+	if err := bot.SendWithContext(short, Text{Content: "over quota"}, limit); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("send over quota = %v, want context.DeadlineExceeded", err)
+	}
+	if state := bot.CircuitBreakerState(); state != CircuitClosed {
+		t.Fatalf("breaker state = %v after a cancelled wait, want closed", state)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+}
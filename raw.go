@@ -0,0 +1,21 @@
+package dingtalk
+
+import "context"
+
+// RawMsg 原始消息，请求体的顶层字段由 Body 直接给出，用于发送本库尚未支持的消息类型或字段
+type RawMsg struct {
+	// 请求体，其中 msgtype 的值作为消息类型
+	Body map[string]any
+}
+
+func (r RawMsg) Type() MsgType {
+	t, _ := r.Body["msgtype"].(string)
+	return MsgType(t)
+}
+
+var _ Msg = RawMsg{}
+
+// SendRaw 发送原始消息，body 中的字段会直接作为请求体的顶层字段，并覆盖处理器设置的同名字段
+func (b *Bot) SendRaw(ctx context.Context, body map[string]any, handlers ...SendHandler) error {
+	return b.SendWithContext(ctx, RawMsg{Body: body}, handlers...)
+}
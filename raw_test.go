@@ -0,0 +1,43 @@
+package dingtalk
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSendRaw(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	body := map[string]any{
+		"msgtype":         "interactiveCard",
+		"interactiveCard": map[string]any{"cardData": "{}"},
+	}
+	if err := bot.SendRaw(context.Background(), body, UUID("uuid-raw")); err != nil {
+		t.Fatal(err)
+	}
+	got := srv.Last(t).JSON(t)
+	if got["msgtype"] != "interactiveCard" {
+		t.Fatalf("msgtype = %v, want interactiveCard", got["msgtype"])
+	}
+	if card, ok := got["interactiveCard"].(map[string]any); !ok || card["cardData"] != "{}" {
+		t.Fatalf("interactiveCard = %v, want it at the top level", got["interactiveCard"])
+	}
+	if got["msgUuid"] != "uuid-raw" {
+		t.Fatalf("msgUuid = %v, want uuid-raw", got["msgUuid"])
+	}
+	if _, nested := got["RawMsg"]; nested {
+		t.Fatal("RawMsg nested in the request body")
+	}
+
+	body["msgUuid"] = "uuid-body"
+	if err := bot.SendRaw(context.Background(), body, UUID("uuid-raw")); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Last(t).JSON(t)["msgUuid"]; got != "uuid-body" {
+		t.Fatalf("msgUuid = %v, want the body to override handlers", got)
+	}
+	if got := (RawMsg{Body: map[string]any{"msgtype": 1}}).Type(); got != "" {
+		t.Fatalf("Type() with non-string msgtype = %q, want empty", got)
+	}
+}
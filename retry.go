@@ -0,0 +1,146 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Drelf2018/req"
+)
+
+// parseRetryAfter 解析秒数或 HTTP 日期格式的 Retry-After 值，无法解析时返回零
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// RetryAfter 返回钉钉响应头 Retry-After 中建议的重试间隔，没有提示时返回零
+func (s SendError) RetryAfter() time.Duration {
+	return s.retryAfter
+}
+
+// IsRateLimited 检测错误是否为发送过于频繁，即错误码为 43 或 130101
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimit) || errors.Is(err, ErrSendTooFast)
+}
+
+// RateLimitRetryAfter 错误为发送过于频繁时返回建议的重试间隔，否则返回零
+func RateLimitRetryAfter(err error) time.Duration {
+	var sendErr SendError
+	if !errors.As(err, &sendErr) || !IsRateLimited(sendErr) {
+		return 0
+	}
+	return sendErr.RetryAfter()
+}
+
+// IsTransientError 检测错误是否为暂时性的，即网络错误、连接意外关闭、钉钉系统繁忙（错误码 -1）或 HTTP 5xx 状态码，
+// 上下文取消或超时不是暂时性的错误
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var sendErr SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.ErrCode == -1 || sendErr.ErrCode >= 500 && sendErr.ErrCode < 600
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// RetryPolicy 发送失败时的重试策略，重试间隔从 Initial 开始指数增长至 Max ，并带有随机抖动
+type RetryPolicy struct {
+	// 最大尝试次数，包括第一次发送，值不大于一时不重试
+	MaxAttempts int
+
+	// 第一次重试前的等待时间
+	Initial time.Duration
+
+	// 重试等待时间的上限，值不为正时不限制
+	Max time.Duration
+
+	// 判断第 attempt 次发送失败后是否重试，为空时使用 IsTransientError
+	ShouldRetry func(attempt int, err error) bool
+}
+
+// shouldRetry 检测第 attempt 次发送失败后是否重试
+func (p *RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(attempt, err)
+	}
+	return IsTransientError(err)
+}
+
+// backoff 返回第 attempt 次发送失败后的等待时间，为指数增长间隔的一半加上不超过一半的随机值，
+// 错误中带有更长的 Retry-After 提示时使用提示
+func (p *RetryPolicy) backoff(attempt int, err error) time.Duration {
+	d := p.Initial
+	for i := 1; i < attempt && (p.Max <= 0 || d < p.Max); i++ {
+		d *= 2
+	}
+	if p.Max > 0 && d > p.Max {
+		d = p.Max
+	}
+	if d > 0 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	var sendErr SendError
+	if errors.As(err, &sendErr) && sendErr.RetryAfter() > d {
+		d = sendErr.RetryAfter()
+	}
+	return d
+}
+
+// Handler 返回为请求设置该重试策略的处理器
+func (p RetryPolicy) Handler() SendHandler {
+	return func(s *Send) error {
+		s.retry = &p
+		return nil
+	}
+}
+
+// Retry 返回发送失败时以指数退避重试的处理器，最多发送 maxAttempts 次，只重试 IsTransientError 判断为暂时性的错误
+func Retry(maxAttempts int, initial, max time.Duration) SendHandler {
+	return RetryPolicy{MaxAttempts: maxAttempts, Initial: initial, Max: max}.Handler()
+}
+
+// postSendWithRetry 按请求的重试策略发送请求，等待重试期间上下文取消时立即返回上下文的错误
+func postSendWithRetry(ctx context.Context, session *req.Session, api *Send) (r SendResponse, err error) {
+	policy := api.retry
+	for attempt := 1; ; attempt++ {
+		r, err = postSend(ctx, session, api)
+		if err == nil || policy == nil || !policy.shouldRetry(attempt, err) {
+			return
+		}
+		timer := time.NewTimer(policy.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return r, fmt.Errorf("dingtalk: retry cancelled after %d attempts: %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
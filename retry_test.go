@@ -0,0 +1,189 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// replyRetryAfter 返回携带 Retry-After 响应头的错误响应
+func replyRetryAfter(code int, retryAfter string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		replyError(code, "send too fast")(w, r)
+	}
+}
+
+func TestSendErrorRetryAfter(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	srv.SetHandler(replyRetryAfter(ErrRateLimit.Code, "5"))
+	err := bot.SendText("limited")
+	var sendErr SendError
+	if !errors.As(err, &sendErr) || sendErr.RetryAfter() != 5*time.Second {
+		t.Fatalf("RetryAfter() = %v, want 5s", sendErr.RetryAfter())
+	}
+	if !IsRateLimited(err) || RateLimitRetryAfter(err) != 5*time.Second {
+		t.Fatalf("IsRateLimited() = %v, RateLimitRetryAfter() = %v", IsRateLimited(err), RateLimitRetryAfter(err))
+	}
+	if got := RateLimitRetryAfter(fmt.Errorf("wrapped: %w", err)); got != 5*time.Second {
+		t.Fatalf("RateLimitRetryAfter(wrapped) = %v, want 5s", got)
+	}
+
+	srv.SetHandler(replyRetryAfter(ErrSendTooFast.Code, time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)))
+	if got := RateLimitRetryAfter(bot.SendText("date")); got < 58*time.Second || got > time.Minute {
+		t.Fatalf("RateLimitRetryAfter() with HTTP date = %v, want about 1m", got)
+	}
+
+	srv.SetHandler(replyRetryAfter(ErrRateLimit.Code, ""))
+	if err := bot.SendText("no hint"); !IsRateLimited(err) || RateLimitRetryAfter(err) != 0 {
+		t.Fatalf("RateLimitRetryAfter() without header = %v, want 0", RateLimitRetryAfter(err))
+	}
+
+	srv.SetHandler(replyRetryAfter(ErrInvalidToken.Code, "5"))
+	if err := bot.SendText("other"); IsRateLimited(err) || RateLimitRetryAfter(err) != 0 {
+		t.Fatalf("non rate limit error: IsRateLimited() = %v, RateLimitRetryAfter() = %v", IsRateLimited(err), RateLimitRetryAfter(err))
+	}
+	if IsRateLimited(nil) || RateLimitRetryAfter(errors.New("plain")) != 0 {
+		t.Fatal("helpers matched non-SendError values")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": 0, "0": 0, "3": 3 * time.Second, "-1": 0, "soon": 0} {
+		if got := parseRetryAfter(value); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+// failFirst 返回前 n 次请求由 fail 响应、之后回复成功的响应，并记录请求次数
+func failFirst(n int32, fail http.HandlerFunc, calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			fail(w, r)
+			return
+		}
+		replyError(0, "ok")(w, r)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	serverError := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}
+	tests := []struct {
+		name     string
+		fail     http.HandlerFunc
+		failures int32
+		attempts int
+		want     error
+		calls    int32
+	}{
+		{"5xx recovered", serverError, 2, 3, nil, 3},
+		{"busy recovered", replyError(-1, "system busy"), 1, 3, nil, 2},
+		{"attempts exhausted", serverError, 5, 3, ErrorCode(http.StatusServiceUnavailable), 3},
+		{"permanent", replyError(ErrInvalidToken.Code, "token is not exist"), 5, 3, ErrInvalidToken, 1},
+		{"disabled", serverError, 1, 1, ErrorCode(http.StatusServiceUnavailable), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			var calls atomic.Int32
+			srv.SetHandler(failFirst(tt.failures, tt.fail, &calls))
+			err := srv.Bot().SendText("retry", Retry(tt.attempts, time.Millisecond, 4*time.Millisecond))
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("SendText() = %v, want %v", err, tt.want)
+			}
+			if got := calls.Load(); got != tt.calls {
+				t.Fatalf("server received %d requests, want %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryCancel(t *testing.T) {
+	srv := newTestServer(t)
+	var calls atomic.Int32
+	srv.SetHandler(failFirst(5, replyError(-1, "system busy"), &calls))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := srv.Bot().SendWithContext(ctx, Text{Content: "retry"}, Retry(5, time.Hour, time.Hour))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendWithContext() = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("SendWithContext() returned after %s, want it to stop waiting when the context ends", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("server received %d requests, want 1", got)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	srv := newTestServer(t)
+	var calls atomic.Int32
+	srv.SetHandler(failFirst(2, replyError(ErrInvalidToken.Code, "token is not exist"), &calls))
+	var attempts []int
+	policy := RetryPolicy{MaxAttempts: 5, Initial: time.Millisecond, ShouldRetry: func(attempt int, err error) bool {
+		attempts = append(attempts, attempt)
+		return errors.Is(err, ErrInvalidToken)
+	}}
+	if err := srv.Bot().SendText("custom", policy.Handler()); err != nil {
+		t.Fatalf("SendText() = %v, want the custom policy to retry ErrInvalidToken", err)
+	}
+	if fmt.Sprint(attempts) != "[1 2]" || calls.Load() != 3 {
+		t.Fatalf("ShouldRetry attempts = %v with %d requests, want [1 2] and 3", attempts, calls.Load())
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&url.Error{Op: "Post", URL: DefaultWebhook, Err: errors.New("connection refused")}, true},
+		{io.EOF, true},
+		{fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{SendError{API: &Send{}, ErrCode: -1}, true},
+		{SendError{API: &Send{}, ErrCode: http.StatusBadGateway}, true},
+		{SendError{API: &Send{}, ErrCode: ErrInvalidToken.Code}, false},
+		{SendError{API: &Send{}, ErrCode: http.StatusNotFound}, false},
+		{context.Canceled, false},
+		{&url.Error{Op: "Post", URL: DefaultWebhook, Err: context.DeadlineExceeded}, false},
+		{errors.New("other"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransientError(tt.err); got != tt.want {
+			t.Errorf("IsTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{Initial: 10 * time.Millisecond, Max: 40 * time.Millisecond}
+	for attempt, max := range map[int]time.Duration{1: 10, 2: 20, 3: 40, 4: 40, 10: 40} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(attempt, nil); d < max/2 || d > max {
+				t.Fatalf("backoff(%d) = %s, want within [%s, %s]", attempt, d, max/2, max)
+			}
+		}
+	}
+	hinted := SendError{API: &Send{}, ErrCode: ErrRateLimit.Code, retryAfter: time.Second}
+	if d := p.backoff(1, hinted); d != time.Second {
+		t.Fatalf("backoff() with Retry-After = %s, want 1s", d)
+	}
+}
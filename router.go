@@ -0,0 +1,76 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoRoute 没有与键对应的机器人，且没有设置默认机器人
+var ErrNoRoute = errors.New("dingtalk: no bot for route")
+
+// BotRouter 根据键选择机器人发送消息
+type BotRouter struct {
+	routes     map[string]*Bot
+	defaultBot *Bot
+	key        func(Msg) string
+
+	mu sync.RWMutex
+}
+
+// NewBotRouter 新建机器人路由
+func NewBotRouter() *BotRouter {
+	return &BotRouter{routes: make(map[string]*Bot)}
+}
+
+// GroupBy 新建以当前机器人为默认机器人的路由，Dispatch 时使用 key 计算消息的键
+func (b *Bot) GroupBy(key func(Msg) string) *BotRouter {
+	r := NewBotRouter().DefaultBot(b)
+	r.key = key
+	return r
+}
+
+// Route 设置键对应的机器人
+func (r *BotRouter) Route(key string, bot *Bot) *BotRouter {
+	r.mu.Lock()
+	r.routes[key] = bot
+	r.mu.Unlock()
+	return r
+}
+
+// DefaultBot 设置找不到键对应的机器人时使用的默认机器人
+func (r *BotRouter) DefaultBot(bot *Bot) *BotRouter {
+	r.mu.Lock()
+	r.defaultBot = bot
+	r.mu.Unlock()
+	return r
+}
+
+// Lookup 返回键对应的机器人，找不到时返回默认机器人
+func (r *BotRouter) Lookup(key string) (*Bot, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if bot, ok := r.routes[key]; ok {
+		return bot, true
+	}
+	return r.defaultBot, r.defaultBot != nil
+}
+
+// Send 使用键对应的机器人发送消息，找不到时使用默认机器人，都没有时返回 ErrNoRoute
+func (r *BotRouter) Send(ctx context.Context, key string, msg Msg, handlers ...SendHandler) error {
+	bot, ok := r.Lookup(key)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNoRoute, key)
+	}
+	return bot.SendWithContext(ctx, msg, handlers...)
+}
+
+// Dispatch 使用 GroupBy 设置的函数计算消息的键，然后调用 Send 发送消息，未设置函数时使用默认机器人
+func (r *BotRouter) Dispatch(ctx context.Context, msg Msg, handlers ...SendHandler) error {
+	var key string
+	if r.key != nil {
+		key = r.key(msg)
+	}
+	return r.Send(ctx, key, msg, handlers...)
+}
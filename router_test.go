@@ -0,0 +1,55 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBotRouter(t *testing.T) {
+	dba, devops, fallback := newTestServer(t), newTestServer(t), newTestServer(t)
+	r := NewBotRouter().Route("database", dba.Bot()).Route("deploy", devops.Bot())
+
+	if err := r.Send(context.Background(), "unknown", Text{Content: "lost"}); !errors.Is(err, ErrNoRoute) {
+		t.Fatalf("Send() without default = %v, want ErrNoRoute", err)
+	}
+	r.DefaultBot(fallback.Bot())
+
+	for key, srv := range map[string]*testServer{"database": dba, "deploy": devops, "unknown": fallback} {
+		if err := r.Send(context.Background(), key, Text{Content: key}); err != nil {
+			t.Fatalf("Send(%s) = %v", key, err)
+		}
+		if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != key {
+			t.Fatalf("Send(%s) routed %v to the wrong bot", key, got)
+		}
+	}
+	for name, srv := range map[string]*testServer{"dba": dba, "devops": devops, "fallback": fallback} {
+		if n := len(srv.Requests()); n != 1 {
+			t.Errorf("%s received %d requests, want 1", name, n)
+		}
+	}
+}
+
+func TestBotGroupBy(t *testing.T) {
+	dba, fallback := newTestServer(t), newTestServer(t)
+	r := fallback.Bot().GroupBy(func(msg Msg) string {
+		if text, ok := msg.(Text); ok && strings.Contains(text.Content, "database") {
+			return "database"
+		}
+		return ""
+	}).Route("database", dba.Bot())
+
+	if err := r.Dispatch(context.Background(), Text{Content: "database is slow"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Dispatch(context.Background(), Text{Content: "cpu is high"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(dba.Requests()) != 1 || len(fallback.Requests()) != 1 {
+		t.Fatalf("dba received %d, fallback received %d, want 1 each", len(dba.Requests()), len(fallback.Requests()))
+	}
+	if bot, ok := NewBotRouter().Lookup("any"); ok || bot != nil {
+		t.Fatal("empty router Lookup() found a bot")
+	}
+}
@@ -0,0 +1,116 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// reportError 将后台发送的错误交给 OnError 处理，未设置时丢弃
+func (b *Bot) reportError(msg Msg, err error) {
+	if hook := b.OnError; hook != nil {
+		b.callHook("OnError", func() { hook(msg, err) })
+	}
+}
+
+// SendAfter 在 d 时间后发送消息，在此之前调用返回的函数或取消上下文可以终止发送。
+// 返回的错误仅表示无法安排发送，发送产生的错误会交给 OnError 处理
+func (b *Bot) SendAfter(ctx context.Context, d time.Duration, msg Msg, handlers ...SendHandler) (context.CancelFunc, error) {
+	if b == nil {
+		return nil, ErrNilBot
+	}
+	if b.IsShutdown() {
+		return nil, ErrClosedBot
+	}
+	if msg == nil {
+		return nil, ErrNilMsg
+	}
+	if d < 0 {
+		return nil, fmt.Errorf("dingtalk: negative delay: %s", d)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	timer := time.NewTimer(d)
+	go func() {
+		defer cancel()
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if err := b.SendWithContext(ctx, msg, handlers...); err != nil {
+			b.reportError(msg, err)
+		}
+	}()
+	return cancel, nil
+}
+
+// newTicker 创建以 d 为间隔的定时器，返回触发通道和停止函数，测试时替换为手动触发的通道
+var newTicker = func(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// SendEvery 以 interval 为间隔调用 fn 生成消息并发送，直到上下文取消后返回上下文的错误。
+// fn 返回空消息时跳过本次发送，fn 和发送产生的错误会交给 OnError 处理
+func (b *Bot) SendEvery(ctx context.Context, interval time.Duration, fn func() (Msg, error), handlers ...SendHandler) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if interval <= 0 {
+		return fmt.Errorf("dingtalk: non-positive interval: %s", interval)
+	}
+	tick, stop := newTicker(interval)
+	defer stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+		}
+		msg, err := fn()
+		if err != nil {
+			b.reportError(msg, err)
+			continue
+		}
+		if msg == nil {
+			continue
+		}
+		if err = b.SendWithContext(ctx, msg, handlers...); err != nil {
+			b.reportError(msg, err)
+		}
+	}
+}
+
+// SendOnChange 立即并以 interval 为间隔调用 fetch ，仅在其返回的 changed 为真时发送消息，
+// 第一次调用只要返回了消息就视为发生变化，直到上下文取消后返回上下文的错误，错误处理与 SendEvery 相同
+func (b *Bot) SendOnChange(ctx context.Context, interval time.Duration, fetch func() (Msg, bool, error), handlers ...SendHandler) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if interval <= 0 {
+		return fmt.Errorf("dingtalk: non-positive interval: %s", interval)
+	}
+	tick, stop := newTicker(interval)
+	defer stop()
+	for first := true; ; first = false {
+		if !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-tick:
+			}
+		}
+		msg, changed, err := fetch()
+		if err != nil {
+			b.reportError(msg, err)
+			continue
+		}
+		if msg == nil || !(changed || first) {
+			continue
+		}
+		if err = b.SendWithContext(ctx, msg, handlers...); err != nil {
+			b.reportError(msg, err)
+		}
+	}
+}
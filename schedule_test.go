@@ -0,0 +1,199 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// notify 收到请求时向通道发送通知并返回发送成功
+func notify(ch chan<- struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		replyError(0, "ok")(w, r)
+		ch <- struct{}{}
+	}
+}
+
+func TestSendAfter(t *testing.T) {
+	srv := newTestServer(t)
+	received := make(chan struct{}, 1)
+	srv.SetHandler(notify(received))
+
+	start := time.Now()
+	if _, err := srv.Bot().SendAfter(context.Background(), 50*time.Millisecond, Text{Content: "delayed"}); err != nil {
+		t.Fatalf("SendAfter() = %v", err)
+	}
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("delayed message was not delivered")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("message delivered after %s, want at least 50ms", elapsed)
+	}
+	if content := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; content != "delayed" {
+		t.Fatalf("content = %v, want delayed", content)
+	}
+}
+
+func TestSendAfterCancel(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	cancel, err := bot.SendAfter(context.Background(), 50*time.Millisecond, Text{Content: "cancelled"})
+	if err != nil {
+		t.Fatalf("SendAfter() = %v", err)
+	}
+	cancel()
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	if _, err := bot.SendAfter(ctx, 50*time.Millisecond, Text{Content: "ctx cancelled"}); err != nil {
+		t.Fatalf("SendAfter() = %v", err)
+	}
+	cancelCtx()
+
+	time.Sleep(100 * time.Millisecond)
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("server received %d requests after cancel, want 0", n)
+	}
+}
+
+func TestSendAfterError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	errs := make(chan error, 1)
+	bot := srv.Bot()
+	bot.OnError = func(_ Msg, err error) { errs <- err }
+
+	if _, err := bot.SendAfter(context.Background(), 0, Text{Content: "failed"}); err != nil {
+		t.Fatalf("SendAfter() = %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("OnError got %v, want ErrInvalidToken", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnError was not called")
+	}
+
+	if _, err := bot.SendAfter(context.Background(), -time.Second, Text{Content: "negative"}); err == nil {
+		t.Fatal("SendAfter() with negative delay = nil, want error")
+	}
+	if _, err := bot.SendAfter(context.Background(), 0, nil); !errors.Is(err, ErrNilMsg) {
+		t.Fatalf("SendAfter(nil) = %v, want ErrNilMsg", err)
+	}
+}
+
+// fakeTicker 替换 newTicker ，返回手动触发的通道
+func fakeTicker(t *testing.T) chan time.Time {
+	t.Helper()
+	tick := make(chan time.Time)
+	old := newTicker
+	newTicker = func(time.Duration) (<-chan time.Time, func()) { return tick, func() {} }
+	t.Cleanup(func() { newTicker = old })
+	return tick
+}
+
+func TestSendEvery(t *testing.T) {
+	tick := fakeTicker(t)
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	var reported []error
+	bot.OnError = func(_ Msg, err error) { reported = append(reported, err) }
+	errFetch := errors.New("fetch failed")
+
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- bot.SendEvery(ctx, time.Hour, func() (Msg, error) {
+			calls++
+			switch calls {
+			case 2, 5:
+				return nil, nil
+			case 3:
+				return nil, errFetch
+			}
+			return Text{Content: "digest"}, nil
+		})
+	}()
+	// 通道无缓冲，第五次触发被接收时前四次已经处理完毕
+	for i := 0; i < 5; i++ {
+		tick <- time.Now()
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendEvery() = %v, want context.Canceled", err)
+	}
+	if calls != 5 {
+		t.Fatalf("fn called %d times, want 5", calls)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Fatalf("server received %d requests, want 2 (nil message and error skipped)", n)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], errFetch) {
+		t.Fatalf("OnError got %v, want the fetch error", reported)
+	}
+
+	if err := bot.SendEvery(context.Background(), 0, nil); err == nil {
+		t.Fatal("SendEvery() with zero interval = nil, want error")
+	}
+}
+
+func TestSendOnChange(t *testing.T) {
+	tick := fakeTicker(t)
+	srv := newTestServer(t)
+	bot := srv.Bot()
+
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- bot.SendOnChange(ctx, time.Hour, func() (Msg, bool, error) {
+			calls++
+			switch {
+			case calls == 1:
+				return nil, false, nil
+			case calls == 5:
+				return Text{Content: "changed"}, true, nil
+			}
+			return Text{Content: "same"}, false, nil
+		})
+	}()
+	// 第一次调用立即执行，之后每次触发调用一次，最后一次触发用于等待前一次处理完毕
+	for i := 0; i < 5; i++ {
+		tick <- time.Now()
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendOnChange() = %v, want context.Canceled", err)
+	}
+	if calls != 6 {
+		t.Fatalf("fetch called %d times, want 6", calls)
+	}
+	requests := srv.Requests()
+	if len(requests) != 1 || requests[0].JSON(t)["text"].(map[string]any)["content"] != "changed" {
+		t.Fatalf("server received %d requests, want only the changed message", len(requests))
+	}
+}
+
+func TestSendOnChangeFirstPoll(t *testing.T) {
+	tick := fakeTicker(t)
+	srv := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- srv.Bot().SendOnChange(ctx, time.Hour, func() (Msg, bool, error) {
+			return Text{Content: "initial"}, false, nil
+		})
+	}()
+	tick <- time.Now()
+	cancel()
+	<-done
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want the first poll only", n)
+	}
+}
@@ -0,0 +1,100 @@
+package dingtalk
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// testToken 测试服务器使用的凭证，格式与钉钉提供的凭证一致
+const testToken = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// testRequest 测试服务器收到的请求
+type testRequest struct {
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// JSON 将请求体解析为对象
+func (r testRequest) JSON(t testing.TB) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal(r.Body, &m); err != nil {
+		t.Fatalf("invalid request body %q: %v", r.Body, err)
+	}
+	return m
+}
+
+// testServer 模拟钉钉接口的测试服务器，记录收到的所有请求
+type testServer struct {
+	*httptest.Server
+
+	// 自定义响应，为空时返回发送成功
+	Handler http.HandlerFunc
+
+	mu       sync.Mutex
+	requests []testRequest
+}
+
+// newTestServer 创建测试服务器，测试结束时自动关闭
+func newTestServer(t testing.TB) *testServer {
+	t.Helper()
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, testRequest{Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+		handler := s.Handler
+		s.mu.Unlock()
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"errcode":0,"errmsg":"ok"}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetHandler 设置自定义响应
+func (s *testServer) SetHandler(h http.HandlerFunc) {
+	s.mu.Lock()
+	s.Handler = h
+	s.mu.Unlock()
+}
+
+// Bot 返回向测试服务器发送消息的机器人
+func (s *testServer) Bot() *Bot {
+	return &Bot{Token: testToken, BaseURL: s.URL}
+}
+
+// Requests 返回收到的所有请求
+func (s *testServer) Requests() []testRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]testRequest(nil), s.requests...)
+}
+
+// Last 返回最后一个请求，未收到请求时测试失败
+func (s *testServer) Last(t testing.TB) testRequest {
+	t.Helper()
+	requests := s.Requests()
+	if len(requests) == 0 {
+		t.Fatal("server received no requests")
+	}
+	return requests[len(requests)-1]
+}
+
+// replyError 返回指定错误码的响应
+func replyError(code int, msg string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"errcode": code, "errmsg": msg})
+	}
+}
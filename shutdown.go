@@ -0,0 +1,24 @@
+package dingtalk
+
+import "errors"
+
+var (
+	// ErrNilBot 在空机器人上调用方法
+	ErrNilBot = errors.New("dingtalk: bot is nil")
+
+	// ErrClosedBot 机器人已通过 Shutdown 关闭
+	ErrClosedBot = errors.New("dingtalk: bot is shut down")
+)
+
+// Shutdown 关闭机器人，之后发送消息都会返回 ErrClosedBot ，已经开始的发送不受影响
+func (b *Bot) Shutdown() {
+	if b == nil {
+		return
+	}
+	b.closed.Store(true)
+}
+
+// IsShutdown 检测机器人是否已关闭
+func (b *Bot) IsShutdown() bool {
+	return b != nil && b.closed.Load()
+}
@@ -0,0 +1,60 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNilBot(t *testing.T) {
+	var bot *Bot
+	tests := map[string]func() error{
+		"Send":            func() error { return bot.Send(Text{Content: "nil"}) },
+		"SendText":        func() error { return bot.SendText("nil") },
+		"SendWithContext": func() error { return bot.SendWithContext(context.Background(), Text{Content: "nil"}) },
+		"NewSend": func() error {
+			_, err := bot.NewSend(context.Background(), Text{Content: "nil"})
+			return err
+		},
+		"Serialize": func() error {
+			_, err := bot.Serialize(context.Background(), Text{Content: "nil"})
+			return err
+		},
+		"WillExceedLimit": func() error {
+			_, err := bot.WillExceedLimit(Text{Content: "nil"})
+			return err
+		},
+		"MarshalText":   func() error { _, err := bot.MarshalText(); return err },
+		"UnmarshalText": func() error { return bot.UnmarshalText([]byte("dingtalk://" + testToken)) },
+		"Validate":      func() error { return bot.Validate() },
+	}
+	for name, call := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, ErrNilBot) {
+				t.Fatalf("%s() error = %v, want ErrNilBot", name, err)
+			}
+		})
+	}
+	bot.Shutdown()
+	if bot.IsShutdown() {
+		t.Fatal("nil bot IsShutdown() = true")
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	if err := bot.SendText("before"); err != nil {
+		t.Fatalf("SendText() before Shutdown = %v", err)
+	}
+	bot.Shutdown()
+	if !bot.IsShutdown() {
+		t.Fatal("IsShutdown() = false after Shutdown")
+	}
+	if err := bot.SendText("after"); !errors.Is(err, ErrClosedBot) {
+		t.Fatalf("SendText() after Shutdown = %v, want ErrClosedBot", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Fatalf("server received %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,41 @@
+package dingtalk
+
+import "context"
+
+// DefaultMaxContentLen 默认的请求体长度上限，单位是字节
+const DefaultMaxContentLen = 20000
+
+// EstimatePayloadSize 估算发送消息时请求体的字节数，与 Send.Body 生成的请求体一致
+func EstimatePayloadSize(msg Msg, at At) (int, error) {
+	data, err := (&Send{Msg: msg, At: at}).body(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// WillExceedLimit 检测消息的请求体是否超过 Bot.MaxContentLen ，未设置时使用 DefaultMaxContentLen 。
+// 请求体通过 EstimatePayloadSize 估算，包括消息转换、自动转义、关键词和默认@配置的影响，
+// 但不会执行处理器和凭证解析器，也不会记录关键词添加
+func (b *Bot) WillExceedLimit(msg Msg) (bool, error) {
+	if b == nil {
+		return false, ErrNilBot
+	}
+	msg, err := b.prepareMsg(context.Background(), msg, false)
+	if err != nil {
+		return false, err
+	}
+	var at At
+	if msg != nil && msg.Type().AtSupported() {
+		at = b.defaultAt()
+	}
+	size, err := EstimatePayloadSize(msg, at)
+	if err != nil {
+		return false, err
+	}
+	limit := b.MaxContentLen
+	if limit <= 0 {
+		limit = DefaultMaxContentLen
+	}
+	return size > limit, nil
+}
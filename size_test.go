@@ -0,0 +1,112 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEstimatePayloadSize(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	at := At{AtMobiles: []string{"13800000000"}}
+	long := strings.Repeat("监控告警 ", 200)
+	for _, msg := range []Msg{
+		Text{Content: long},
+		Link{Title: "title", Text: long, MessageURL: "https://example.com", PicURL: "https://example.com/a.png"},
+		Markdown{Title: "title", Text: long},
+		ActionCard{Title: "title", Text: long, SingleTitle: "more", SingleURL: "https://example.com"},
+		ActionsCard{Title: "title", Text: long, Btns: []ActionCardBtn{{Title: "a", ActionURL: "https://example.com/a"}, {Title: "b", ActionURL: "https://example.com/b"}}},
+		FeedCard{Links: []FeedCardLink{{Title: long, MessageURL: "https://example.com", PicURL: "https://example.com/a.png"}}},
+	} {
+		size, err := EstimatePayloadSize(msg, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handlers := []SendHandler{}
+		if msg.Type().AtSupported() {
+			handlers = append(handlers, AtMobile(at.AtMobiles...))
+		}
+		if err = bot.SendWithContext(context.Background(), msg, handlers...); err != nil {
+			t.Fatal(err)
+		}
+		actual := len(srv.Last(t).Body)
+		if diff := math.Abs(float64(size-actual)) / float64(actual); diff > 0.1 {
+			t.Errorf("%s: estimate %d differs from sent body %d by %.1f%%", msg.Type(), size, actual, diff*100)
+		}
+	}
+}
+
+func TestWillExceedLimit(t *testing.T) {
+	bot := &Bot{Token: testToken, MaxContentLen: 100}
+	exceed, err := bot.WillExceedLimit(Text{Content: "short"})
+	if err != nil || exceed {
+		t.Fatalf("WillExceedLimit(short) = %v, %v, want false", exceed, err)
+	}
+	exceed, err = bot.WillExceedLimit(Text{Content: strings.Repeat("a", 100)})
+	if err != nil || !exceed {
+		t.Fatalf("WillExceedLimit(long) = %v, %v, want true", exceed, err)
+	}
+
+	// 估算包括自动添加的关键词和默认@配置
+	content := strings.Repeat("a", 40)
+	bot = &Bot{Token: testToken, MaxContentLen: 80, Keywords: []string{strings.Repeat("k", 30)}}
+	if exceed, _ = (&Bot{Token: testToken, MaxContentLen: 80}).WillExceedLimit(Text{Content: content}); exceed {
+		t.Fatal("WillExceedLimit() without keywords = true, want false")
+	}
+	if exceed, _ = bot.WillExceedLimit(Text{Content: content}); !exceed {
+		t.Fatal("WillExceedLimit() with injected keyword = false, want true")
+	}
+
+	exceed, err = (&Bot{Token: testToken}).WillExceedLimit(Text{Content: strings.Repeat("a", DefaultMaxContentLen)})
+	if err != nil || !exceed {
+		t.Fatalf("WillExceedLimit() with default limit = %v, %v, want true", exceed, err)
+	}
+}
+
+func TestWillExceedLimitSkipsHandlers(t *testing.T) {
+	var called bool
+	bot := &Bot{
+		Token:                testToken,
+		MaxContentLen:        200,
+		Keywords:             []string{"告警"},
+		LogKeywordInjections: true,
+		DefaultAt:            At{AtMobiles: []string{"13800000000"}},
+		DefaultHandlers: []SendHandler{func(s *Send) error {
+			called = true
+			s.Msg = Text{Content: strings.Repeat("a", 1000)}
+			return nil
+		}},
+		TokenResolver: func(context.Context) (string, error) {
+			called = true
+			return "", errors.New("resolver should not be called")
+		},
+	}
+	bot.WrapMsg(prefixText("[prod] "))
+	exceed, err := bot.WillExceedLimit(Text{Content: "disk full"})
+	if err != nil || exceed {
+		t.Fatalf("WillExceedLimit() = %v, %v, want false", exceed, err)
+	}
+	if called {
+		t.Fatal("WillExceedLimit() ran a handler or the token resolver")
+	}
+	if len(bot.KeywordLog) != 0 {
+		t.Fatalf("WillExceedLimit() recorded keyword injections: %v", bot.KeywordLog)
+	}
+
+	msg := Text{Content: "[prod] disk full告警"}
+	want, err := EstimatePayloadSize(msg, bot.DefaultAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.MaxContentLen = want
+	if exceed, _ = bot.WillExceedLimit(Text{Content: "disk full"}); exceed {
+		t.Fatalf("WillExceedLimit() = true at a limit of exactly %d bytes", want)
+	}
+	bot.MaxContentLen = want - 1
+	if exceed, _ = bot.WillExceedLimit(Text{Content: "disk full"}); !exceed {
+		t.Fatalf("WillExceedLimit() = false at a limit of %d bytes, want the wrapper, keyword and default At counted", want-1)
+	}
+}
@@ -0,0 +1,54 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// SlogHandler 使用指定级别记录每次发送的处理器，发送前记录消息类型、@配置和时间，发送后记录耗时和错误码，记录器为空时使用 slog.Default()
+func SlogHandler(logger *slog.Logger, level slog.Level) SendHandler {
+	return func(s *Send) error {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		var start time.Time
+		s.OnBeforeSend(func(ctx context.Context, s *Send) {
+			start = time.Now()
+			l.LogAttrs(ctx, level, "dingtalk: sending message",
+				slog.String("msg_type", string(msgTypeOf(s.Msg))),
+				slog.Bool("at_all", s.At.IsAtAll),
+				slog.Any("at_mobiles", s.At.AtMobiles),
+				slog.Any("at_user_ids", s.At.AtUserIDs),
+				slog.Time("timestamp", start),
+			)
+		})
+		s.OnAfterSend(func(ctx context.Context, s *Send, resp *SendResponse, err error) {
+			attrs := []slog.Attr{
+				slog.String("msg_type", string(msgTypeOf(s.Msg))),
+				slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			}
+			var sendErr SendError
+			if resp != nil {
+				attrs = append(attrs, slog.Int("err_code", resp.ErrCode))
+			} else if errors.As(err, &sendErr) {
+				attrs = append(attrs, slog.Int("err_code", sendErr.ErrCode))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			l.LogAttrs(ctx, level, "dingtalk: message send finished", attrs...)
+		})
+		return nil
+	}
+}
+
+// msgTypeOf 返回消息类型，消息为空时返回空字符串
+func msgTypeOf(msg Msg) MsgType {
+	if msg == nil {
+		return ""
+	}
+	return msg.Type()
+}
@@ -0,0 +1,61 @@
+package dingtalk
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	before := time.Now()
+	if err := bot.SendText("hello", SlogHandler(logger, slog.LevelWarn), AtMobile("13800000000"), AtUserID("user")); err != nil {
+		t.Fatalf("SendText() = %v", err)
+	}
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	if err := bot.Send(Markdown{Title: "t", Text: "t"}, SlogHandler(logger, slog.LevelWarn)); err == nil {
+		t.Fatal("Send() error = nil, want API error")
+	}
+
+	logs := decodeLogs(t, buf)
+	if len(logs) != 4 {
+		t.Fatalf("got %d log entries, want 4: %v", len(logs), logs)
+	}
+	for _, entry := range logs {
+		if entry["level"] != "WARN" {
+			t.Errorf("level = %v, want WARN", entry["level"])
+		}
+	}
+
+	sending := logs[0]
+	if sending["msg"] != "dingtalk: sending message" || sending["msg_type"] != "text" || sending["at_all"] != false {
+		t.Errorf("sending entry = %v", sending)
+	}
+	if mobiles, _ := sending["at_mobiles"].([]any); len(mobiles) != 1 || mobiles[0] != "13800000000" {
+		t.Errorf("at_mobiles = %v", sending["at_mobiles"])
+	}
+	if ids, _ := sending["at_user_ids"].([]any); len(ids) != 1 || ids[0] != "user" {
+		t.Errorf("at_user_ids = %v", sending["at_user_ids"])
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, sending["timestamp"].(string)); err != nil || ts.Before(before.Add(-time.Second)) {
+		t.Errorf("timestamp = %v", sending["timestamp"])
+	}
+
+	finished := logs[1]
+	if finished["msg"] != "dingtalk: message send finished" || finished["err_code"] != float64(0) || finished["error"] != nil {
+		t.Errorf("finished entry = %v", finished)
+	}
+	if _, ok := finished["latency_ms"].(float64); !ok {
+		t.Errorf("latency_ms = %v", finished["latency_ms"])
+	}
+
+	failed := logs[3]
+	if failed["msg_type"] != "markdown" || failed["err_code"] != float64(ErrInvalidToken.Code) || failed["error"] == nil {
+		t.Errorf("failed entry = %v", failed)
+	}
+}
@@ -0,0 +1,244 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateExt 模板文件的扩展名
+const TemplateExt = ".tmpl"
+
+// NewTemplate 注册一个模板，模板名一般为 StructName.FieldName 的形式，例如 Text.Content
+func (b *Bot) NewTemplate(name, text string) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if b.Template == nil {
+		b.Template = template.New("dingtalk")
+	}
+	_, err := b.Template.New(name).Parse(text)
+	if err != nil {
+		return fmt.Errorf("dingtalk: failed to parse template %q: %w", name, err)
+	}
+	return nil
+}
+
+// ParseDirFS 读取文件系统中指定目录下所有扩展名为 .tmpl 的文件（不递归子目录），
+// 去掉扩展名后作为模板名，使用文件内容注册模板
+func (b *Bot) ParseDirFS(fsys fs.FS, dir string) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	files, err := fs.Glob(fsys, path.Join(dir, "*"+TemplateExt))
+	if err != nil {
+		return fmt.Errorf("dingtalk: failed to read template dir %q: %w", dir, err)
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("dingtalk: failed to read template file %q: %w", file, err)
+		}
+		name := strings.TrimSuffix(path.Base(file), TemplateExt)
+		if err = b.NewTemplate(name, string(data)); err != nil {
+			return fmt.Errorf("dingtalk: failed to parse template file %q: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// ParseDir 读取本地目录下所有扩展名为 .tmpl 的文件并注册为模板，详见 ParseDirFS
+func (b *Bot) ParseDir(dir string) error {
+	return b.ParseDirFS(os.DirFS(dir), ".")
+}
+
+var (
+	// ErrNilTemplate 机器人没有注册任何模板
+	ErrNilTemplate = errors.New("dingtalk: template is nil")
+
+	// ErrTemplateNotFound 未注册指定名称的模板
+	ErrTemplateNotFound = errors.New("dingtalk: template not found")
+)
+
+// LookupTemplate 返回名为 name 的模板，未注册任何模板或找不到时返回空
+func (b *Bot) LookupTemplate(name string) *template.Template {
+	if b == nil || b.Template == nil {
+		return nil
+	}
+	return b.Template.Lookup(name)
+}
+
+// ExecuteTemplate 执行名为 name 的模板并将结果写入 w ，未注册任何模板时返回 ErrNilTemplate
+func (b *Bot) ExecuteTemplate(w io.Writer, name string, data any) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if b.Template == nil {
+		return ErrNilTemplate
+	}
+	return b.Template.ExecuteTemplate(w, name, data)
+}
+
+// TemplateNames 返回所有已注册模板的名称，按名称排序，未注册任何模板时返回空
+func (b *Bot) TemplateNames() []string {
+	if b == nil || b.Template == nil {
+		return nil
+	}
+	var names []string
+	for _, t := range b.Template.Templates() {
+		if t.Tree != nil {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateName 返回带有前缀的模板名，前缀为空时直接返回 name
+func templateName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// executeTemplate 执行名为 name 的模板并返回结果
+func (b *Bot) executeTemplate(name string, data any) (string, error) {
+	t := b.LookupTemplate(name)
+	if t == nil {
+		return "", fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("dingtalk: failed to execute template %q: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+// SendTemplateText 执行名为 tmplName.Text.Content 的模板，并将结果作为文本类型消息发送
+func (b *Bot) SendTemplateText(ctx context.Context, data any, tmplName string, handlers ...SendHandler) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	content, err := b.executeTemplate(templateName(tmplName, "Text.Content"), data)
+	if err != nil {
+		return err
+	}
+	return b.SendWithContext(ctx, Text{Content: content}, handlers...)
+}
+
+// SendTemplateMarkdown 执行名为 tmplName.Markdown.Title 和 tmplName.Markdown.Text 的模板，并将结果作为 markdown 类型消息发送
+func (b *Bot) SendTemplateMarkdown(ctx context.Context, data any, tmplName string, handlers ...SendHandler) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	title, err := b.executeTemplate(templateName(tmplName, "Markdown.Title"), data)
+	if err != nil {
+		return err
+	}
+	text, err := b.executeTemplate(templateName(tmplName, "Markdown.Text"), data)
+	if err != nil {
+		return err
+	}
+	return b.SendWithContext(ctx, Markdown{Title: title, Text: text}, handlers...)
+}
+
+// Parse 将消息中所有非空的字符串字段注册为模板，模板名为 StructName.FieldName ，字段值为模板内容
+func (b *Bot) Parse(msg Msg) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if msg == nil {
+		return ErrNilMsg
+	}
+	value := reflect.Indirect(reflect.ValueOf(msg))
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("dingtalk: unsupported template msg type %T", msg)
+	}
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		if !field.IsExported() || field.Type.Kind() != reflect.String {
+			continue
+		}
+		text := value.FieldByIndex(field.Index).String()
+		if text == "" {
+			continue
+		}
+		if err := b.NewTemplate(value.Type().Name()+"."+field.Name, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseMsgTemplates 依次调用 Parse 注册所有消息中的模板，返回所有错误合并后的结果
+func (b *Bot) ParseMsgTemplates(msgs ...Msg) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	var errs []error
+	for _, msg := range msgs {
+		if err := b.Parse(msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// templateMsgTypes 可以通过 ApplyTemplate 构建的消息类型
+var templateMsgTypes = map[string]reflect.Type{
+	"Text":        reflect.TypeOf(Text{}),
+	"Link":        reflect.TypeOf(Link{}),
+	"Markdown":    reflect.TypeOf(Markdown{}),
+	"ActionCard":  reflect.TypeOf(ActionCard{}),
+	"ActionsCard": reflect.TypeOf(ActionsCard{}),
+}
+
+// ApplyTemplate 执行所有名为 tmplName.StructName.FieldName 的模板，并使用结果构建 StructName 类型的消息，
+// 例如 alert.Markdown.Title 和 alert.Markdown.Text ，同一前缀下的模板只能属于一种消息类型
+func (b *Bot) ApplyTemplate(tmplName string, data any) (Msg, error) {
+	if b == nil {
+		return nil, ErrNilBot
+	}
+	prefix := templateName(tmplName, "")
+	var value reflect.Value
+	for _, name := range b.TemplateNames() {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		typeName, fieldName, ok := strings.Cut(rest, ".")
+		if !ok || strings.Contains(fieldName, ".") {
+			continue
+		}
+		typ, ok := templateMsgTypes[typeName]
+		if !ok {
+			continue
+		}
+		if !value.IsValid() {
+			value = reflect.New(typ).Elem()
+		} else if value.Type() != typ {
+			return nil, fmt.Errorf("dingtalk: templates %q define multiple msg types: %s and %s", tmplName, value.Type().Name(), typeName)
+		}
+		field := value.FieldByName(fieldName)
+		if !field.IsValid() || field.Kind() != reflect.String {
+			return nil, fmt.Errorf("dingtalk: template %q does not match a string field of %s", name, typeName)
+		}
+		text, err := b.executeTemplate(name, data)
+		if err != nil {
+			return nil, err
+		}
+		field.SetString(text)
+	}
+	if !value.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, prefix+"*")
+	}
+	return value.Interface().(Msg), nil
+}
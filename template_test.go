@@ -0,0 +1,223 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// templateData 测试模板使用的数据
+type templateData struct {
+	Service string
+	Status  string
+}
+
+func TestParseDir(t *testing.T) {
+	bot := &Bot{}
+	if err := bot.ParseDir("testdata/templates"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Markdown.Text", "Markdown.Title", "Text.Content"}
+	if got := bot.TemplateNames(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TemplateNames() = %q, want %q", got, want)
+	}
+	var sb strings.Builder
+	if err := bot.ExecuteTemplate(&sb, "Text.Content", templateData{"api", "down"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != "api is down" {
+		t.Fatalf("Text.Content = %q, want %q", got, "api is down")
+	}
+}
+
+func TestParseDirFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/Text.Content.tmpl":    {Data: []byte("{{.Service}}")},
+		"templates/Link.Title.tmpl":      {Data: []byte("{{.Status}}")},
+		"templates/sub/Link.Text.tmpl":   {Data: []byte("ignored")},
+		"templates/Markdown.Text.txt":    {Data: []byte("ignored")},
+		"other/Markdown.Title.tmpl":      {Data: []byte("ignored")},
+		"templates/ActionCard.Text.tmpl": {Data: []byte("{{.Service}}: {{.Status}}")},
+	}
+	bot := &Bot{}
+	if err := bot.ParseDirFS(fsys, "templates"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ActionCard.Text", "Link.Title", "Text.Content"}
+	if got := bot.TemplateNames(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TemplateNames() = %q, want %q", got, want)
+	}
+}
+
+func TestParseDirFSError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Text.Content.tmpl":   {Data: []byte("{{.Service}}")},
+		"Markdown.Title.tmpl": {Data: []byte("{{.Service")},
+	}
+	err := (&Bot{}).ParseDirFS(fsys, ".")
+	if err == nil {
+		t.Fatal("ParseDirFS() error = nil, want parse error")
+	}
+	if !strings.Contains(err.Error(), "Markdown.Title.tmpl") {
+		t.Fatalf("ParseDirFS() error = %q, want filename in error", err)
+	}
+}
+
+func TestParseDirNilBot(t *testing.T) {
+	var bot *Bot
+	if err := bot.ParseDir("testdata/templates"); err != ErrNilBot {
+		t.Fatalf("ParseDir() error = %v, want ErrNilBot", err)
+	}
+}
+
+func TestSendTemplate(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot()
+	if err := bot.ParseDir("testdata/templates"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.NewTemplate("alert.Text.Content", "[alert] {{.Service}}"); err != nil {
+		t.Fatal(err)
+	}
+	data := templateData{"api", "down"}
+
+	if err := bot.SendTemplateText(context.Background(), data, "", AtAll); err != nil {
+		t.Fatalf("SendTemplateText() = %v", err)
+	}
+	body := srv.Last(t).JSON(t)
+	if got := body["text"].(map[string]any)["content"]; got != "api is down" {
+		t.Fatalf("content = %q, want %q", got, "api is down")
+	}
+	if got := body["at"].(map[string]any)["isAtAll"]; got != true {
+		t.Fatalf("isAtAll = %v, want handlers applied", got)
+	}
+
+	if err := bot.SendTemplateText(context.Background(), data, "alert"); err != nil {
+		t.Fatalf("SendTemplateText(alert) = %v", err)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != "[alert] api" {
+		t.Fatalf("content = %q, want %q", got, "[alert] api")
+	}
+
+	if err := bot.SendTemplateMarkdown(context.Background(), data, ""); err != nil {
+		t.Fatalf("SendTemplateMarkdown() = %v", err)
+	}
+	markdown := srv.Last(t).JSON(t)["markdown"].(map[string]any)
+	if markdown["title"] != "api alert" || markdown["text"] != "### api\n\n- status: down" {
+		t.Fatalf("markdown = %q", markdown)
+	}
+
+	before := len(srv.Requests())
+	if err := bot.SendTemplateMarkdown(context.Background(), data, "alert"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("SendTemplateMarkdown(alert) = %v, want ErrTemplateNotFound", err)
+	}
+	if err := (&Bot{}).SendTemplateText(context.Background(), data, ""); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("SendTemplateText() without templates = %v, want ErrTemplateNotFound", err)
+	}
+	if n := len(srv.Requests()); n != before {
+		t.Fatalf("missing templates sent %d requests", n-before)
+	}
+}
+
+func TestParseMsgTemplates(t *testing.T) {
+	bot := &Bot{}
+	err := bot.ParseMsgTemplates(
+		Text{Content: "{{.Service}}"},
+		Markdown{Title: "{{.Service}}", Text: "{{.Status"},
+		Link{Title: "{{.Status}}", MessageURL: "https://example.com"},
+	)
+	if err == nil {
+		t.Fatal("ParseMsgTemplates() error = nil, want parse error")
+	}
+	if !strings.Contains(err.Error(), "Markdown.Text") {
+		t.Fatalf("ParseMsgTemplates() error = %q, want the invalid field name", err)
+	}
+	for _, name := range []string{"Text.Content", "Markdown.Title", "Link.Title", "Link.MessageURL"} {
+		if bot.LookupTemplate(name) == nil {
+			t.Errorf("template %q was not registered", name)
+		}
+	}
+
+	err = bot.ParseMsgTemplates(Text{Content: "{{"}, nil, Link{Title: "{{"})
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 3 {
+		t.Fatalf("ParseMsgTemplates() error = %v, want 3 joined errors", err)
+	}
+	if !errors.Is(err, ErrNilMsg) {
+		t.Fatalf("ParseMsgTemplates() error = %v, want ErrNilMsg", err)
+	}
+}
+
+func TestTemplateZeroBot(t *testing.T) {
+	bot := &Bot{}
+	if got := bot.LookupTemplate("Text.Content"); got != nil {
+		t.Fatalf("LookupTemplate() = %v, want nil", got)
+	}
+	if err := bot.ExecuteTemplate(io.Discard, "Text.Content", nil); !errors.Is(err, ErrNilTemplate) {
+		t.Fatalf("ExecuteTemplate() = %v, want ErrNilTemplate", err)
+	}
+	if got := bot.TemplateNames(); got != nil {
+		t.Fatalf("TemplateNames() = %q, want nil", got)
+	}
+
+	var nilBot *Bot
+	if nilBot.LookupTemplate("Text.Content") != nil || nilBot.TemplateNames() != nil {
+		t.Fatal("nil bot template accessors returned values")
+	}
+	if err := nilBot.ExecuteTemplate(io.Discard, "Text.Content", nil); !errors.Is(err, ErrNilBot) {
+		t.Fatalf("nil bot ExecuteTemplate() = %v, want ErrNilBot", err)
+	}
+
+	if err := bot.NewTemplate("Text.Content", "{{.}}"); err != nil {
+		t.Fatal(err)
+	}
+	if bot.LookupTemplate("Text.Content") == nil || bot.LookupTemplate("missing") != nil {
+		t.Fatal("LookupTemplate() after NewTemplate returned wrong templates")
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	bot := &Bot{}
+	for name, text := range map[string]string{
+		"alert.Markdown.Title": "{{.Service}} alert",
+		"alert.Markdown.Text":  "### {{.Service}} is {{.Status}}",
+		"notice.Text.Content":  "{{.Service}}: {{.Status}}",
+		"card.ActionCard.Text": "{{.Status}}",
+		"bad.Markdown.Missing": "x",
+		"mixed.Text.Content":   "x",
+		"mixed.Link.Title":     "x",
+	} {
+		if err := bot.NewTemplate(name, text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := templateData{"api", "down"}
+
+	msg, err := bot.ApplyTemplate("alert", data)
+	if err != nil {
+		t.Fatalf("ApplyTemplate(alert) = %v", err)
+	}
+	if want := (Markdown{Title: "api alert", Text: "### api is down"}); msg != want {
+		t.Fatalf("ApplyTemplate(alert) = %#v, want %#v", msg, want)
+	}
+	if msg, err = bot.ApplyTemplate("notice", data); err != nil || msg != (Text{Content: "api: down"}) {
+		t.Fatalf("ApplyTemplate(notice) = %#v, %v", msg, err)
+	}
+	if msg, err = bot.ApplyTemplate("card", data); err != nil || msg != (ActionCard{Text: "down"}) {
+		t.Fatalf("ApplyTemplate(card) = %#v, %v", msg, err)
+	}
+
+	if _, err = bot.ApplyTemplate("missing", data); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("ApplyTemplate(missing) = %v, want ErrTemplateNotFound", err)
+	}
+	if _, err = bot.ApplyTemplate("bad", data); err == nil || !strings.Contains(err.Error(), "bad.Markdown.Missing") {
+		t.Fatalf("ApplyTemplate(bad) = %v, want unknown field error", err)
+	}
+	if _, err = bot.ApplyTemplate("mixed", data); err == nil || !strings.Contains(err.Error(), "multiple msg types") {
+		t.Fatalf("ApplyTemplate(mixed) = %v, want multiple msg types error", err)
+	}
+}
@@ -0,0 +1,90 @@
+package dingtalk
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanName 每次发送时创建的跨度名称
+const SpanName = "dingtalk.Send"
+
+// WithTracer 设置链路追踪器
+func (b *Bot) WithTracer(tracer trace.Tracer) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.Tracer = tracer
+	return b
+}
+
+// TraceAll 开启完整的链路追踪，每次发送都会创建一个跨度并记录消息类型、错误码和耗时，
+// 开启 InjectTraceInFooter 时消息正文末尾的链路信息为该跨度，值为空时关闭所有链路追踪
+func (b *Bot) TraceAll(tracer trace.Tracer) *Bot {
+	if b == nil {
+		return nil
+	}
+	b.Tracer = tracer
+	b.traceSends = tracer != nil
+	if tracer == nil {
+		b.InjectTraceInFooter = false
+	}
+	return b
+}
+
+// startSpan 为一次发送创建跨度
+func (b *Bot) startSpan(ctx context.Context, tracer trace.Tracer, msg Msg) (context.Context, trace.Span) {
+	return tracer.Start(ctx, SpanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("dingtalk.msg_type", string(msgTypeOf(msg)))),
+	)
+}
+
+// recordSpan 在上下文中的跨度上记录响应的错误码和耗时
+func recordSpan(ctx context.Context, r SendResponse, latency time.Duration) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("dingtalk.err_code", r.ErrCode),
+		attribute.Int64("dingtalk.latency_ms", latency.Milliseconds()),
+	)
+}
+
+// endSpan 记录错误并结束跨度
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// injectTraceFooter 设置了链路追踪器且开启 InjectTraceInFooter 时，
+// 将上下文中的 traceID/spanID 添加至 text 、 markdown 和 actionCard 类型消息正文的末尾
+func (b *Bot) injectTraceFooter(ctx context.Context, msg Msg) Msg {
+	if b.Tracer == nil || !b.InjectTraceInFooter {
+		return msg
+	}
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() {
+		return msg
+	}
+	footer := "\n\ntrace: " + sc.TraceID().String() + "/" + sc.SpanID().String()
+	switch m := msg.(type) {
+	case Text:
+		m.Content += footer
+		return m
+	case Markdown:
+		m.Text += footer
+		return m
+	case ActionCard:
+		m.Text += footer
+		return m
+	case ActionsCard:
+		m.Text += footer
+		return m
+	default:
+		return msg
+	}
+}
@@ -0,0 +1,146 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// spanContext 返回携带固定链路信息的上下文
+func spanContext() (context.Context, string) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18},
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc), "trace: 0102030405060708090a0b0c0d0e0f10/1112131415161718"
+}
+
+func TestInjectTraceInFooter(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().WithTracer(noop.NewTracerProvider().Tracer("test"))
+	bot.InjectTraceInFooter = true
+	ctx, footer := spanContext()
+	for _, c := range []struct {
+		msg   Msg
+		field string
+	}{
+		{Text{Content: "disk full"}, "content"},
+		{Markdown{Title: "disk", Text: "disk full"}, "text"},
+		{ActionCard{Title: "disk", Text: "disk full", SingleTitle: "more", SingleURL: "https://example.com"}, "text"},
+	} {
+		data, err := bot.Serialize(ctx, c.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `disk full\n\n`+footer) {
+			t.Errorf("%s: payload %s missing footer %q", c.msg.Type(), data, footer)
+		}
+		if err = bot.SendWithContext(ctx, c.msg); err != nil {
+			t.Fatal(err)
+		}
+		body := srv.Last(t).JSON(t)[string(c.msg.Type())].(map[string]any)
+		if !strings.HasSuffix(body[c.field].(string), footer) {
+			t.Errorf("%s: sent %s = %q, want footer", c.msg.Type(), c.field, body[c.field])
+		}
+	}
+}
+
+func TestInjectTraceInFooterDisabled(t *testing.T) {
+	ctx, _ := spanContext()
+	for _, bot := range []*Bot{
+		{Token: testToken, InjectTraceInFooter: true},
+		(&Bot{Token: testToken}).WithTracer(noop.NewTracerProvider().Tracer("test")),
+	} {
+		data, err := bot.Serialize(ctx, Text{Content: "disk full"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "trace:") {
+			t.Errorf("payload %s contains footer without both tracer and InjectTraceInFooter", data)
+		}
+	}
+	bot := (&Bot{Token: testToken, InjectTraceInFooter: true}).WithTracer(noop.NewTracerProvider().Tracer("test"))
+	data, _ := bot.Serialize(context.Background(), Text{Content: "disk full"})
+	if strings.Contains(string(data), "trace:") {
+		t.Errorf("payload %s contains footer without a span in context", data)
+	}
+}
+
+// recordingSpan 记录属性和结束状态的跨度
+type recordingSpan struct {
+	noop.Span
+	attrs map[attribute.Key]attribute.Value
+	err   error
+	ended bool
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.err = err }
+
+func (s *recordingSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+// recordingTracer 创建 recordingSpan 的链路追踪器
+type recordingTracer struct {
+	noop.Tracer
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{attrs: map[attribute.Key]attribute.Value{}}
+	config := trace.NewSpanStartConfig(opts...)
+	for _, a := range config.Attributes() {
+		span.attrs[a.Key] = a.Value
+	}
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func TestTraceAll(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().TraceAll(noop.NewTracerProvider().Tracer("test"))
+	bot.InjectTraceInFooter = true
+	if err := bot.SendText("noop"); err != nil {
+		t.Fatalf("SendText() with noop tracer = %v", err)
+	}
+	srv.SetHandler(replyError(ErrInvalidToken.Code, "invalid token"))
+	if err := bot.SendText("noop"); err == nil {
+		t.Fatal("SendText() error = nil, want API error")
+	}
+
+	tracer := &recordingTracer{}
+	bot.TraceAll(tracer)
+	bot.SendMarkdown("title", "text")
+	if len(tracer.spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if !span.ended || !errors.Is(span.err, ErrInvalidToken) {
+		t.Fatalf("span ended = %v, err = %v", span.ended, span.err)
+	}
+	if span.attrs["dingtalk.msg_type"].AsString() != "markdown" || span.attrs["dingtalk.err_code"].AsInt64() != int64(ErrInvalidToken.Code) {
+		t.Fatalf("span attributes = %v", span.attrs)
+	}
+	if _, ok := span.attrs["dingtalk.latency_ms"]; !ok {
+		t.Fatal("span missing dingtalk.latency_ms")
+	}
+
+	bot.TraceAll(nil)
+	if bot.Tracer != nil || bot.InjectTraceInFooter || bot.traceSends {
+		t.Fatal("TraceAll(nil) did not disable tracing")
+	}
+	bot.SendText("untraced")
+	if len(tracer.spans) != 1 {
+		t.Fatal("TraceAll(nil) still created spans")
+	}
+}
@@ -0,0 +1,32 @@
+package dingtalk
+
+import "unicode/utf8"
+
+// truncateRunes 将字符串截断至最多 maxRunes 个字符，发生截断时在末尾添加 "..."
+func truncateRunes(s string, maxRunes int) string {
+	if maxRunes < 0 {
+		maxRunes = 0
+	}
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	return string([]rune(s)[:maxRunes]) + "..."
+}
+
+// TruncateContent 返回内容被截断至最多 maxRunes 个字符的新文本类型消息，发生截断时在末尾添加 "..."
+func (t Text) TruncateContent(maxRunes int) Text {
+	t.Content = truncateRunes(t.Content, maxRunes)
+	return t
+}
+
+// TruncateText 返回内容被截断至最多 maxRunes 个字符的新链接类型消息，不会截断标题
+func (l Link) TruncateText(maxRunes int) Link {
+	l.Text = truncateRunes(l.Text, maxRunes)
+	return l
+}
+
+// TruncateText 返回文本内容被截断至最多 maxRunes 个字符的新 markdown 类型消息，不会截断标题
+func (m Markdown) TruncateText(maxRunes int) Markdown {
+	m.Text = truncateRunes(m.Text, maxRunes)
+	return m
+}
@@ -0,0 +1,36 @@
+package dingtalk
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	const s = "磁盘空间不足🚨请处理"
+	for _, c := range []struct {
+		max  int
+		want string
+	}{
+		{0, "..."},
+		{5, "磁盘空间不..."},
+		{6, "磁盘空间不足..."},
+		{7, "磁盘空间不足🚨..."},
+		{10, s},
+		{100, s},
+		{-1, "..."},
+	} {
+		if got := (Text{Content: s}).TruncateContent(c.max).Content; got != c.want {
+			t.Errorf("Text.TruncateContent(%d) = %q, want %q", c.max, got, c.want)
+		}
+		m := Markdown{Title: s, Text: s}.TruncateText(c.max)
+		if m.Text != c.want || m.Title != s {
+			t.Errorf("Markdown.TruncateText(%d) = %+v, want text %q and untouched title", c.max, m, c.want)
+		}
+		l := Link{Title: s, Text: s}.TruncateText(c.max)
+		if l.Text != c.want || l.Title != s {
+			t.Errorf("Link.TruncateText(%d) = %+v, want text %q and untouched title", c.max, l, c.want)
+		}
+	}
+	orig := Text{Content: s}
+	orig.TruncateContent(1)
+	if orig.Content != s {
+		t.Fatal("TruncateContent modified the receiver")
+	}
+}
@@ -0,0 +1,186 @@
+package dingtalk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Validator 可自行检测是否有效的消息
+type Validator interface {
+	Validate() error
+}
+
+// ValidateMsg 检测消息是否有效，消息实现了 Validator 时调用其 Validate 方法
+func ValidateMsg(msg Msg) error {
+	if msg == nil {
+		return ErrNilMsg
+	}
+	if v, ok := msg.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// FieldError 消息中的一个无效字段
+type FieldError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// ValidationError 消息校验错误，包含所有无效的字段
+type ValidationError struct {
+	Type   MsgType
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f.Error()
+	}
+	return fmt.Sprintf("dingtalk: invalid %s msg: %s", e.Type, strings.Join(fields, "; "))
+}
+
+// add 添加一个无效字段
+func (e *ValidationError) add(field, value, reason string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Value: value, Reason: reason})
+}
+
+// err 存在无效字段时返回自身，否则返回空
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// checkURL 检测链接是否为有效的 http 或 https 链接，有效时返回空字符串，否则返回原因
+func checkURL(rawURL string) string {
+	if rawURL == "" {
+		return "empty URL"
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err.Error()
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "scheme must be http or https"
+	}
+	if u.Host == "" {
+		return "missing host"
+	}
+	return ""
+}
+
+// Validate 检测点击消息跳转的 URL 和非空的图片地址是否为有效的 http 或 https 链接
+func (l Link) Validate() error {
+	e := &ValidationError{Type: l.Type()}
+	if reason := checkURL(l.MessageURL); reason != "" {
+		e.add("MessageURL", l.MessageURL, reason)
+	}
+	if l.PicURL != "" {
+		if reason := checkURL(l.PicURL); reason != "" {
+			e.add("PicURL", l.PicURL, reason)
+		}
+	}
+	return e.err()
+}
+
+var _ Validator = Link{}
+
+// MaxActionsCardBtns 独立跳转 actionCard 类型消息的按钮数量上限
+const MaxActionsCardBtns = 5
+
+var (
+	// ErrNoBtns 独立跳转 actionCard 类型消息没有按钮
+	ErrNoBtns = errors.New("dingtalk: actions card has no btns")
+
+	// ErrTooManyBtns 独立跳转 actionCard 类型消息的按钮数量超过上限
+	ErrTooManyBtns = errors.New("dingtalk: actions card has too many btns")
+)
+
+// Validate 检测按钮标题是否为空，跳转的 URL 是否为有效的 http 或 https 链接
+func (b ActionCardBtn) Validate() error {
+	e := &ValidationError{Type: MsgActionCard}
+	if b.Title == "" {
+		e.add("Title", b.Title, "empty title")
+	}
+	if reason := checkURL(b.ActionURL); reason != "" {
+		e.add("ActionURL", b.ActionURL, reason)
+	}
+	return e.err()
+}
+
+var _ Validator = ActionCardBtn{}
+
+// Validate 检测按钮数量是否在 1 到 MaxActionsCardBtns 之间，以及每个按钮和非空的按钮排列方式是否有效，返回所有错误合并后的结果
+func (a ActionsCard) Validate() error {
+	var errs []error
+	if len(a.Btns) == 0 {
+		errs = append(errs, ErrNoBtns)
+	} else if len(a.Btns) > MaxActionsCardBtns {
+		errs = append(errs, fmt.Errorf("%w: %d > %d", ErrTooManyBtns, len(a.Btns), MaxActionsCardBtns))
+	}
+	if a.BtnOrientation != "" {
+		if err := a.BtnOrientation.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i, btn := range a.Btns {
+		if err := btn.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("dingtalk: invalid btn %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var _ Validator = ActionsCard{}
+
+// MaxKeywordLen 自定义关键词的最大长度，单位是字节
+const MaxKeywordLen = 64
+
+// tokenPattern 钉钉提供的凭证格式，即 64 位小写十六进制字符串
+var tokenPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Validate 检测机器人的配置，返回所有错误合并后的结果：凭证为空时必须设置凭证解析器，
+// 凭证不为空时必须为 64 位小写十六进制字符串，安全密钥不为空时必须以 SEC 开头，关键词不能为空且不超过 64 字节，超时时间不能为负。
+// 凭证为 TestWebhookToken 或设置了接口地址时只检测凭证是否为空，不检测凭证格式，因为代理或模拟接口可能使用其他格式的凭证
+func (b *Bot) Validate() error {
+	if b == nil {
+		return ErrNilBot
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var errs []error
+	switch {
+	case b.Token == "":
+		if b.TokenResolver == nil {
+			errs = append(errs, ErrMissingToken)
+		}
+	case b.Token == TestWebhookToken, b.BaseURL != "":
+	case !tokenPattern.MatchString(b.Token):
+		errs = append(errs, fmt.Errorf("dingtalk: invalid token format: %q", maskToken(b.Token)))
+	}
+	if b.Secret != "" && !strings.HasPrefix(b.Secret, "SEC") {
+		errs = append(errs, errors.New("dingtalk: secret must start with SEC"))
+	}
+	for i, keyword := range b.Keywords {
+		if keyword == "" {
+			errs = append(errs, fmt.Errorf("dingtalk: keyword %d is empty", i))
+		} else if len(keyword) > MaxKeywordLen {
+			errs = append(errs, fmt.Errorf("dingtalk: keyword %d is too long: %d > %d bytes", i, len(keyword), MaxKeywordLen))
+		}
+	}
+	if b.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("dingtalk: negative timeout: %s", b.Timeout))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinkValidate(t *testing.T) {
+	for _, c := range []struct {
+		messageURL, picURL string
+		fields             []string
+	}{
+		{"https://example.com/a", "", nil},
+		{"http://example.com", "https://example.com/a.png", nil},
+		{"example.com", "", []string{"MessageURL"}},
+		{"ftp://example.com/file", "", []string{"MessageURL"}},
+		{"", "", []string{"MessageURL"}},
+		{"https://", "", []string{"MessageURL"}},
+		{"ftp://example.com", "example.com/a.png", []string{"MessageURL", "PicURL"}},
+	} {
+		l := Link{Title: "t", Text: "x", MessageURL: c.messageURL, PicURL: c.picURL}
+		err := ValidateMsg(l)
+		if c.fields == nil {
+			if err != nil {
+				t.Errorf("ValidateMsg(%q, %q) error = %v", c.messageURL, c.picURL, err)
+			}
+			continue
+		}
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Errorf("ValidateMsg(%q, %q) error = %v, want ValidationError", c.messageURL, c.picURL, err)
+			continue
+		}
+		if ve.Type != MsgLink || len(ve.Fields) != len(c.fields) {
+			t.Errorf("ValidateMsg(%q, %q) = %v, want fields %q", c.messageURL, c.picURL, ve, c.fields)
+			continue
+		}
+		for i, field := range c.fields {
+			if ve.Fields[i].Field != field {
+				t.Errorf("ValidateMsg(%q, %q) field %d = %s, want %s", c.messageURL, c.picURL, i, ve.Fields[i].Field, field)
+			}
+		}
+	}
+	if err := ValidateMsg(nil); err != ErrNilMsg {
+		t.Fatalf("ValidateMsg(nil) error = %v, want ErrNilMsg", err)
+	}
+}
+
+func TestActionsCardValidate(t *testing.T) {
+	btns := func(n int) []ActionCardBtn {
+		b := make([]ActionCardBtn, n)
+		for i := range b {
+			b[i] = ActionCardBtn{Title: "btn", ActionURL: "https://example.com"}
+		}
+		return b
+	}
+	tests := []struct {
+		name string
+		card ActionsCard
+		want error
+	}{
+		{"zero", ActionsCard{}, ErrNoBtns},
+		{"one", ActionsCard{Btns: btns(1)}, nil},
+		{"five", ActionsCard{Btns: btns(5), BtnOrientation: BtnOrientationHorizontal}, nil},
+		{"six", ActionsCard{Btns: btns(6)}, ErrTooManyBtns},
+		{"orientation", ActionsCard{Btns: btns(1), BtnOrientation: "2"}, ErrInvalidBtnOrientation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMsg(tt.card)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateMsg() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ValidateMsg() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	card := ActionsCard{Btns: []ActionCardBtn{{Title: "ok", ActionURL: "https://example.com"}, {ActionURL: "ftp://example.com"}}}
+	err := ValidateMsg(card)
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Fields) != 2 {
+		t.Fatalf("ValidateMsg() = %v, want both fields of btn 1 reported", err)
+	}
+	if !strings.Contains(err.Error(), "btn 1") {
+		t.Fatalf("ValidateMsg() = %q, want btn index", err)
+	}
+}
+
+func TestBotValidate(t *testing.T) {
+	bot := &Bot{Token: "ABC", Secret: "secret", Keywords: []string{"", strings.Repeat("k", MaxKeywordLen+1)}, Timeout: -time.Second}
+	err := bot.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want every violation")
+	}
+	for _, want := range []string{
+		"dingtalk: invalid token format",
+		"dingtalk: secret must start with SEC",
+		"dingtalk: keyword 0 is empty",
+		"dingtalk: keyword 1 is too long: 65 > 64 bytes",
+		"dingtalk: negative timeout: -1s",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %q, missing %q", err, want)
+		}
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 5 {
+		t.Fatalf("Validate() joined %d errors, want 5", n)
+	}
+
+	valid := []*Bot{
+		{Token: testToken, Secret: "SECsecret", Keywords: []string{"告警"}},
+		{Token: TestWebhookToken},
+		{Token: "proxy-token", BaseURL: "http://localhost:8080/robot"},
+		{BaseURL: "http://localhost:8080/robot", TokenResolver: func(context.Context) (string, error) { return "proxy-token", nil }},
+		{TokenResolver: func(context.Context) (string, error) { return testToken, nil }},
+	}
+	for _, b := range valid {
+		if err := b.Validate(); err != nil {
+			t.Errorf("Validate(%#v) = %v, want nil", b, err)
+		}
+	}
+	if err := (&Bot{}).Validate(); !errors.Is(err, ErrMissingToken) {
+		t.Fatalf("Validate() without token = %v, want ErrMissingToken", err)
+	}
+	// 接口地址只免除凭证格式检测，凭证仍然不能为空
+	if err := (&Bot{BaseURL: "http://localhost:8080/robot"}).Validate(); !errors.Is(err, ErrMissingToken) {
+		t.Fatalf("Validate() with BaseURL but without token = %v, want ErrMissingToken", err)
+	}
+	if err := (*Bot)(nil).Validate(); !errors.Is(err, ErrNilBot) {
+		t.Fatalf("Validate() on nil bot = %v, want ErrNilBot", err)
+	}
+}
@@ -0,0 +1,178 @@
+package dingtalk
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchInterval 监听配置文件时默认的轮询间隔
+const DefaultWatchInterval = time.Second
+
+// ErrWatching 机器人已经在监听配置文件
+var ErrWatching = errors.New("dingtalk: config watcher already running")
+
+// configWatcher 配置文件监听器
+type configWatcher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// loadConfig 读取并解析配置文件，然后合并至当前机器人，每分钟发送消息限制量不会被合并
+func (b *Bot) loadConfig(path string, parser func([]byte) (*Bot, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("dingtalk: failed to read config %q: %w", path, err)
+	}
+	c, err := parser(data)
+	if err != nil {
+		return fmt.Errorf("dingtalk: failed to parse config %q: %w", path, err)
+	}
+	if c == nil {
+		return fmt.Errorf("dingtalk: parser returned nil bot for config %q", path)
+	}
+	b.setConfig(c, true)
+	return nil
+}
+
+// watchLogger 返回记录监听错误的记录器，未设置记录器时使用 slog.Default()
+func (b *Bot) watchLogger() *slog.Logger {
+	if logger := b.logger(); logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// reloadConfig 重新读取配置文件，失败时记录错误
+func (b *Bot) reloadConfig(path string, parser func([]byte) (*Bot, error)) {
+	if err := b.loadConfig(path, parser); err != nil {
+		b.watchLogger().Error("dingtalk: failed to reload config", slog.String("path", path), slog.String("error", err.Error()))
+	}
+}
+
+// pollConfig 以 interval 为间隔轮询配置文件，文件修改时间或大小变化后重新读取
+func (b *Bot) pollConfig(w *configWatcher, info os.FileInfo, path string, interval time.Duration, parser func([]byte) (*Bot, error)) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	modTime, size := info.ModTime(), info.Size()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+			continue
+		}
+		modTime, size = info.ModTime(), info.Size()
+		b.reloadConfig(path, parser)
+	}
+}
+
+// notifyConfig 通过文件系统通知监听配置文件，文件被写入、创建或重命名后重新读取。
+// 监听的是文件所在目录，因此编辑器通过重命名替换文件时也能收到通知
+func (b *Bot) notifyConfig(w *configWatcher, watcher *fsnotify.Watcher, path string, parser func([]byte) (*Bot, error)) {
+	defer close(w.done)
+	defer watcher.Close()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == path && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				b.reloadConfig(path, parser)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			b.watchLogger().Error("dingtalk: config watcher error", slog.String("path", path), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// WatchConfig 读取配置文件并监听其变化，文件变化后使用 parser 解析文件内容，并将得到的配置合并至当前机器人。
+// 间隔为零时通过文件系统通知监听，为正时以 interval 为间隔轮询文件修改时间和大小，为负时使用 DefaultWatchInterval 轮询。
+// 首次读取失败时返回错误，之后的错误会通过机器人的记录器记录，未设置记录器时使用 slog.Default()
+func (b *Bot) WatchConfig(path string, interval time.Duration, parser func([]byte) (*Bot, error)) error {
+	if b == nil {
+		return ErrNilBot
+	}
+	if parser == nil {
+		return fmt.Errorf("dingtalk: nil config parser")
+	}
+	if interval < 0 {
+		interval = DefaultWatchInterval
+	}
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("dingtalk: failed to stat config %q: %w", path, err)
+	}
+	w := &configWatcher{stop: make(chan struct{}), done: make(chan struct{})}
+	b.mu.Lock()
+	if b.watcher != nil {
+		b.mu.Unlock()
+		return ErrWatching
+	}
+	b.watcher = w
+	b.mu.Unlock()
+	if interval > 0 {
+		if err = b.loadConfig(path, parser); err != nil {
+			b.resetWatcher()
+			return err
+		}
+		go b.pollConfig(w, info, path, interval, parser)
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		b.resetWatcher()
+		return fmt.Errorf("dingtalk: failed to watch config %q: %w", path, err)
+	}
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		b.resetWatcher()
+		return fmt.Errorf("dingtalk: failed to watch config %q: %w", path, err)
+	}
+	if err = b.loadConfig(path, parser); err != nil {
+		watcher.Close()
+		b.resetWatcher()
+		return err
+	}
+	go b.notifyConfig(w, watcher, path, parser)
+	return nil
+}
+
+// resetWatcher 启动监听失败时清除已登记的监听器
+func (b *Bot) resetWatcher() {
+	b.mu.Lock()
+	b.watcher = nil
+	b.mu.Unlock()
+}
+
+// UnwatchConfig 停止监听配置文件并等待监听协程退出，未在监听时不做任何操作
+func (b *Bot) UnwatchConfig() error {
+	if b == nil {
+		return ErrNilBot
+	}
+	b.mu.Lock()
+	w := b.watcher
+	b.watcher = nil
+	b.mu.Unlock()
+	if w != nil {
+		close(w.stop)
+		<-w.done
+	}
+	return nil
+}
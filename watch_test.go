@@ -0,0 +1,126 @@
+package dingtalk
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseTOML 解析只包含字符串键值对的简单 TOML 配置
+func parseTOML(data []byte) (*Bot, error) {
+	b := &Bot{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		value, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in line %q: %w", line, err)
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			b.Name = value
+		case "token":
+			b.Token = value
+		case "secret":
+			b.Secret = value
+		}
+	}
+	return b, sc.Err()
+}
+
+// writeConfig 写入 TOML 配置文件
+func writeConfig(t *testing.T, path, token string) {
+	t.Helper()
+	data := fmt.Sprintf("# dingtalk\nname = %q\ntoken = %q\n", "watch", token)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitToken 等待机器人的凭证变为 want
+func waitToken(t *testing.T, b *Bot, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		b.mu.RLock()
+		token := b.Token
+		b.mu.RUnlock()
+		if token == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("token was not reloaded to %q", want)
+}
+
+func TestWatchConfig(t *testing.T) {
+	for name, interval := range map[string]time.Duration{"poll": 10 * time.Millisecond, "fsnotify": 0} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dingtalk.toml")
+			writeConfig(t, path, "first")
+			bot := &Bot{Limit: 20}
+			if err := bot.WatchConfig(path, interval, parseTOML); err != nil {
+				t.Fatalf("WatchConfig() = %v", err)
+			}
+			t.Cleanup(func() { bot.UnwatchConfig() })
+			if bot.Token != "first" || bot.Name != "watch" {
+				t.Fatalf("initial config = %q %q", bot.Name, bot.Token)
+			}
+			if err := bot.WatchConfig(path, interval, parseTOML); !errors.Is(err, ErrWatching) {
+				t.Fatalf("second WatchConfig() = %v, want ErrWatching", err)
+			}
+
+			writeConfig(t, path, "second-token")
+			waitToken(t, bot, "second-token")
+			if bot.Limit != 20 {
+				t.Fatalf("limit = %d after reload, want 20 kept", bot.Limit)
+			}
+
+			if err := bot.UnwatchConfig(); err != nil {
+				t.Fatalf("UnwatchConfig() = %v", err)
+			}
+			writeConfig(t, path, "third-token-after-unwatch")
+			time.Sleep(50 * time.Millisecond)
+			if bot.Token != "second-token" {
+				t.Fatalf("token = %q after UnwatchConfig, want unchanged", bot.Token)
+			}
+		})
+	}
+}
+
+func TestWatchConfigError(t *testing.T) {
+	dir := t.TempDir()
+	bot := &Bot{}
+	if err := bot.WatchConfig(filepath.Join(dir, "missing.toml"), 0, parseTOML); err == nil {
+		t.Fatal("WatchConfig() on missing file = nil, want error")
+	}
+	path := filepath.Join(dir, "invalid.toml")
+	if err := os.WriteFile(path, []byte("token = unquoted\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.WatchConfig(path, 0, parseTOML); err == nil {
+		t.Fatal("WatchConfig() on invalid file = nil, want error")
+	}
+	if err := bot.WatchConfig(path, 0, nil); err == nil {
+		t.Fatal("WatchConfig() with nil parser = nil, want error")
+	}
+	writeConfig(t, path, "valid")
+	if err := bot.WatchConfig(path, 0, parseTOML); err != nil {
+		t.Fatalf("WatchConfig() after failed attempts = %v", err)
+	}
+	bot.UnwatchConfig()
+}
@@ -0,0 +1,29 @@
+package dingtalk
+
+// WrapMsg 注册一个消息转换函数，每次发送时在处理器执行前调用，例如为正文添加前缀，
+// 多次调用时按注册的相反顺序执行，即最后注册的最先执行，函数返回空时发送失败并返回 ErrNilMsg
+func (b *Bot) WrapMsg(fn func(Msg) Msg) *Bot {
+	if b == nil {
+		return nil
+	}
+	if fn == nil {
+		return b
+	}
+	b.mu.Lock()
+	b.wrappers = append(append(make([]func(Msg) Msg, 0, len(b.wrappers)+1), b.wrappers...), fn)
+	b.mu.Unlock()
+	return b
+}
+
+// wrapMsg 依次调用所有消息转换函数
+func (b *Bot) wrapMsg(msg Msg) (Msg, error) {
+	b.mu.RLock()
+	wrappers := b.wrappers
+	b.mu.RUnlock()
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		if msg = wrappers[i](msg); msg == nil {
+			return nil, ErrNilMsg
+		}
+	}
+	return msg, nil
+}
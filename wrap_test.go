@@ -0,0 +1,59 @@
+package dingtalk
+
+import (
+	"errors"
+	"testing"
+)
+
+// prefixText 返回为文本消息添加前缀的转换函数
+func prefixText(prefix string) func(Msg) Msg {
+	return func(m Msg) Msg {
+		if t, ok := m.(Text); ok {
+			t.Content = prefix + t.Content
+			return t
+		}
+		return m
+	}
+}
+
+func TestWrapMsg(t *testing.T) {
+	srv := newTestServer(t)
+	bot := srv.Bot().WrapMsg(prefixText("[A] ")).WrapMsg(nil).WrapMsg(prefixText("[B] "))
+
+	var seen string
+	err := bot.SendText("deploy", UpdateMsg(func(t Text) Text {
+		seen = t.Content
+		return t
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[A] [B] deploy"; seen != want {
+		t.Fatalf("handler saw %q, want %q", seen, want)
+	}
+	if got := srv.Last(t).JSON(t)["text"].(map[string]any)["content"]; got != "[A] [B] deploy" {
+		t.Fatalf("content = %v, want [A] [B] deploy", got)
+	}
+}
+
+func TestWrapMsgNil(t *testing.T) {
+	srv := newTestServer(t)
+	var called bool
+	bot := srv.Bot().WrapMsg(func(Msg) Msg {
+		called = true
+		return nil
+	}).WrapMsg(func(Msg) Msg { return nil })
+
+	if err := bot.SendText("dropped"); !errors.Is(err, ErrNilMsg) {
+		t.Fatalf("SendText() = %v, want ErrNilMsg", err)
+	}
+	if called {
+		t.Fatal("earlier wrapper called after a later one returned nil")
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("server received %d requests, want 0", n)
+	}
+	if (*Bot)(nil).WrapMsg(prefixText("x")) != nil {
+		t.Fatal("WrapMsg() on nil bot returned non-nil")
+	}
+}